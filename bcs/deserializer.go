@@ -0,0 +1,191 @@
+package bcs
+
+import (
+	"encoding/binary"
+	"math/big"
+)
+
+// Unmarshaler is implemented by types which read their own BCS layout
+type Unmarshaler interface {
+	UnmarshalBCS(d *Deserializer)
+}
+
+// Deserializer reads BCS values from a buffer, the first error is kept and
+// every following read returns zero values, check it with Error
+type Deserializer struct {
+	source []byte
+	pos    int
+	err    error
+}
+
+func NewDeserializer(b []byte) *Deserializer {
+	return &Deserializer{source: b}
+}
+
+// Deserialize reads v from b, trailing bytes are an error
+func Deserialize(v Unmarshaler, b []byte) error {
+	d := NewDeserializer(b)
+	v.UnmarshalBCS(d)
+	if d.err != nil {
+		return d.err
+	}
+
+	if d.Remaining() != 0 {
+		return ErrRemainingBytes
+	}
+	return nil
+}
+
+func (d *Deserializer) SetError(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+func (d *Deserializer) Error() error {
+	return d.err
+}
+
+func (d *Deserializer) Remaining() int {
+	return len(d.source) - d.pos
+}
+
+func (d *Deserializer) Bool() bool {
+	switch d.U8() {
+	case 0:
+		return false
+	case 1:
+		return true
+	default:
+		d.SetError(ErrInvalidBool)
+		return false
+	}
+}
+
+func (d *Deserializer) U8() uint8 {
+	b := d.FixedBytes(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (d *Deserializer) U16() uint16 {
+	b := d.FixedBytes(2)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(b)
+}
+
+func (d *Deserializer) U32() uint32 {
+	b := d.FixedBytes(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func (d *Deserializer) U64() uint64 {
+	b := d.FixedBytes(8)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+func (d *Deserializer) U128() *big.Int {
+	return d.bigUint(16)
+}
+
+func (d *Deserializer) U256() *big.Int {
+	return d.bigUint(32)
+}
+
+func (d *Deserializer) bigUint(size int) *big.Int {
+	b := d.FixedBytes(size)
+	if b == nil {
+		return new(big.Int)
+	}
+
+	be := make([]byte, size)
+	for i := range b {
+		be[size-1-i] = b[i]
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+func (d *Deserializer) Uleb128() uint32 {
+	var v uint64
+	for shift := 0; shift < 32; shift += 7 {
+		b := d.U8()
+		if d.err != nil {
+			return 0
+		}
+
+		v |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			if v > 0xffffffff {
+				break
+			}
+			return uint32(v)
+		}
+	}
+
+	d.SetError(ErrInvalidUleb128)
+	return 0
+}
+
+// FixedBytes reads n bytes without a length prefix
+func (d *Deserializer) FixedBytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+
+	if n < 0 || d.Remaining() < n {
+		d.SetError(ErrNotEnoughBytes)
+		return nil
+	}
+
+	b := make([]byte, n)
+	copy(b, d.source[d.pos:d.pos+n])
+	d.pos += n
+	return b
+}
+
+// ReadBytes reads a vector<u8>
+func (d *Deserializer) ReadBytes() []byte {
+	return d.FixedBytes(int(d.Uleb128()))
+}
+
+func (d *Deserializer) ReadStr() string {
+	return string(d.ReadBytes())
+}
+
+func (d *Deserializer) Struct(v Unmarshaler) {
+	if d.err != nil {
+		return
+	}
+	v.UnmarshalBCS(d)
+}
+
+// DeserializeSequence reads a vector of BCS values, newItem returns an empty item to read into
+func DeserializeSequence[T Unmarshaler](d *Deserializer, newItem func() T) []T {
+	l := d.Uleb128()
+	if d.err != nil {
+		return nil
+	}
+
+	// the length is untrusted, the allocation is bounded by the remaining bytes
+	size := int(l)
+	if size > d.Remaining() {
+		size = d.Remaining()
+	}
+	items := make([]T, 0, size)
+	for i := uint32(0); i < l && d.err == nil; i++ {
+		item := newItem()
+		d.Struct(item)
+		items = append(items, item)
+	}
+	return items
+}
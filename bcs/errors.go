@@ -0,0 +1,12 @@
+package bcs
+
+import "errors"
+
+var (
+	ErrIntOverflow    = errors.New("bcs: integer overflow")
+	ErrInvalidBool    = errors.New("bcs: invalid bool")
+	ErrInvalidUleb128 = errors.New("bcs: invalid uleb128")
+	ErrNotEnoughBytes = errors.New("bcs: not enough bytes")
+	ErrRemainingBytes = errors.New("bcs: remaining bytes after deserialization")
+	ErrInvalidVariant = errors.New("bcs: invalid enum variant")
+)
@@ -0,0 +1,146 @@
+package bcs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+)
+
+// Marshaler is implemented by types which know their own BCS layout,
+// docs in https://github.com/diem/bcs
+type Marshaler interface {
+	MarshalBCS(s *Serializer)
+}
+
+// Serializer writes BCS values into a buffer, the first error is kept and
+// every following write is a no-op, check it with Error
+type Serializer struct {
+	buf bytes.Buffer
+	err error
+}
+
+func NewSerializer() *Serializer {
+	return &Serializer{}
+}
+
+// Serialize returns the BCS bytes of v
+func Serialize(v Marshaler) ([]byte, error) {
+	s := NewSerializer()
+	v.MarshalBCS(s)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.ToBytes(), nil
+}
+
+func (s *Serializer) SetError(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+func (s *Serializer) Error() error {
+	return s.err
+}
+
+func (s *Serializer) ToBytes() []byte {
+	return s.buf.Bytes()
+}
+
+func (s *Serializer) Bool(v bool) {
+	if v {
+		s.U8(1)
+	} else {
+		s.U8(0)
+	}
+}
+
+func (s *Serializer) U8(v uint8) {
+	if s.err != nil {
+		return
+	}
+	s.buf.WriteByte(v)
+}
+
+func (s *Serializer) U16(v uint16) {
+	s.FixedBytes(binary.LittleEndian.AppendUint16(nil, v))
+}
+
+func (s *Serializer) U32(v uint32) {
+	s.FixedBytes(binary.LittleEndian.AppendUint32(nil, v))
+}
+
+func (s *Serializer) U64(v uint64) {
+	s.FixedBytes(binary.LittleEndian.AppendUint64(nil, v))
+}
+
+func (s *Serializer) U128(v *big.Int) {
+	s.bigUint(v, 16)
+}
+
+func (s *Serializer) U256(v *big.Int) {
+	s.bigUint(v, 32)
+}
+
+func (s *Serializer) bigUint(v *big.Int, size int) {
+	if v == nil || v.Sign() < 0 || v.BitLen() > size*8 {
+		s.SetError(ErrIntOverflow)
+		return
+	}
+
+	b := v.FillBytes(make([]byte, size))
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	s.FixedBytes(b)
+}
+
+// Uleb128 writes lengths and enum variants
+func (s *Serializer) Uleb128(v uint32) {
+	for v >= 0x80 {
+		s.U8(byte(v&0x7f) | 0x80)
+		v >>= 7
+	}
+	s.U8(byte(v))
+}
+
+// FixedBytes writes b without the length prefix, e.g. an address
+func (s *Serializer) FixedBytes(b []byte) {
+	if s.err != nil {
+		return
+	}
+	s.buf.Write(b)
+}
+
+// WriteBytes writes a vector<u8>
+func (s *Serializer) WriteBytes(b []byte) {
+	s.Uleb128(uint32(len(b)))
+	s.FixedBytes(b)
+}
+
+func (s *Serializer) WriteStr(v string) {
+	s.WriteBytes([]byte(v))
+}
+
+func (s *Serializer) Struct(v Marshaler) {
+	if s.err != nil {
+		return
+	}
+	v.MarshalBCS(s)
+}
+
+// SerializeSequence writes a vector of BCS values
+func SerializeSequence[T Marshaler](s *Serializer, items []T) {
+	s.Uleb128(uint32(len(items)))
+	for _, item := range items {
+		s.Struct(item)
+	}
+}
+
+// SerializeBytes returns the vector<u8> encoding of b, the layout of a
+// vector<u8> argument in an entry function or a script
+func SerializeBytes(b []byte) []byte {
+	s := NewSerializer()
+	s.WriteBytes(b)
+	return s.ToBytes()
+}
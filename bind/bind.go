@@ -0,0 +1,248 @@
+// Package bind generates typed Go bindings of Move modules from their ABI, like abigen does for
+// Solidity: entry functions become payload constructors, view functions typed callers on a module
+// binding and structs Go types decoded from AccountResourceByType
+package bind
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+type tmplData struct {
+	Package        string
+	Module         string
+	ModuleId       string
+	EntryFunctions []*tmplFunction
+	ViewFunctions  []*tmplFunction
+	Structs        []*tmplStruct
+}
+
+type tmplFunction struct {
+	Name       string
+	GoName     string
+	TypeParams int
+	Params     []*tmplField
+	MoveParams string
+	Returns    []*tmplField
+}
+
+type tmplStruct struct {
+	Name       string
+	GoName     string
+	IsEvent    bool
+	IsResource bool
+	TypeParams int
+	Fields     []*tmplField
+}
+
+type tmplField struct {
+	Name     string
+	GoName   string
+	GoType   string
+	MoveType string
+}
+
+// Generate returns the formatted Go source of the bindings of abi in package pkg
+func Generate(abi *types.MoveModuleABI, pkg string) ([]byte, error) {
+	if abi == nil || abi.Name == "" {
+		return nil, types.ErrABINull
+	}
+
+	address, err := types.ParseAccountAddress(abi.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	if pkg == "" {
+		pkg = strings.ReplaceAll(abi.Name, "_", "")
+	}
+
+	m := &typeMapper{module: address.String() + "::" + abi.Name, structs: make(map[string]string)}
+	for _, st := range abi.Structs {
+		m.structs[st.Name] = goName(st.Name)
+	}
+
+	data := &tmplData{
+		Package:  pkg,
+		Module:   goName(abi.Name) + "Module",
+		ModuleId: m.module,
+	}
+
+	for _, f := range abi.ExposedFunctions {
+		if !f.IsEntry && !f.IsView {
+			continue
+		}
+
+		function := &tmplFunction{
+			Name:       f.Name,
+			GoName:     goName(f.Name),
+			TypeParams: len(f.GenericTypeParams),
+		}
+
+		var moveParams []string
+		for _, param := range f.Params {
+			if param == "signer" || param == "&signer" {
+				continue
+			}
+			function.Params = append(function.Params, &tmplField{
+				Name:     fmt.Sprintf("arg%d", len(function.Params)),
+				GoType:   m.paramType(param),
+				MoveType: param,
+			})
+			moveParams = append(moveParams, fmt.Sprintf("%q", param))
+		}
+		function.MoveParams = strings.Join(moveParams, ", ")
+
+		if f.IsView {
+			for i, ret := range f.Returns {
+				function.Returns = append(function.Returns, &tmplField{
+					Name:     fmt.Sprintf("r%d", i),
+					GoType:   m.valueType(ret),
+					MoveType: ret,
+				})
+			}
+			data.ViewFunctions = append(data.ViewFunctions, function)
+		} else {
+			data.EntryFunctions = append(data.EntryFunctions, function)
+		}
+	}
+
+	for _, st := range abi.Structs {
+		if st.IsNative {
+			continue
+		}
+
+		s := &tmplStruct{
+			Name:       st.Name,
+			GoName:     goName(st.Name),
+			IsEvent:    st.IsEvent,
+			IsResource: hasAbility(st.Abilities, "key"),
+			TypeParams: len(st.GenericTypeParams),
+		}
+		for _, field := range st.Fields {
+			s.Fields = append(s.Fields, &tmplField{
+				Name:     field.Name,
+				GoName:   goName(field.Name),
+				GoType:   m.valueType(field.Type),
+				MoveType: field.Type,
+			})
+		}
+		data.Structs = append(data.Structs, s)
+	}
+
+	var buf bytes.Buffer
+	if err = bindTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// typeMapper maps Move types to Go types, structs of module map to their generated types
+type typeMapper struct {
+	module  string
+	structs map[string]string
+}
+
+// paramType is the Go type of a param, values client.CoerceArguments accepts
+func (m *typeMapper) paramType(t string) string {
+	switch t {
+	case "bool", "u8", "u16", "u32", "u64":
+		return goUint(t)
+	case "u128", "u256":
+		return "*big.Int"
+	case "address":
+		return "types.AccountAddress"
+	case "vector<u8>":
+		return "[]byte"
+	case "0x1::string::String":
+		return "string"
+	}
+
+	switch {
+	case strings.HasPrefix(t, "vector<"):
+		return "[]" + m.paramType(elemType(t, "vector<"))
+	case strings.HasPrefix(t, "0x1::object::Object<"):
+		return "types.AccountAddress"
+	case strings.HasPrefix(t, "0x1::option::Option<"):
+		// an empty slice is none
+		return "[]" + m.paramType(elemType(t, "0x1::option::Option<"))
+	}
+	return "interface{}"
+}
+
+// valueType is the Go type a returned or stored value decodes into by encoding/json
+func (m *typeMapper) valueType(t string) string {
+	switch t {
+	case "bool", "u8", "u16", "u32":
+		return goUint(t)
+	case "u64":
+		return "types.U64"
+	case "u128", "u256":
+		return "types.Uint"
+	case "address":
+		return "types.AccountAddress"
+	case "vector<u8>":
+		return "types.HexBytes"
+	case "0x1::string::String":
+		return "string"
+	}
+
+	switch {
+	case strings.HasPrefix(t, "vector<"):
+		return "[]" + m.valueType(elemType(t, "vector<"))
+	case strings.HasPrefix(t, "0x1::object::Object<"):
+		return "types.Object"
+	case strings.HasPrefix(t, "0x1::option::Option<"):
+		return "types.Option[" + m.valueType(elemType(t, "0x1::option::Option<")) + "]"
+	}
+
+	// structs of this module, generic ones keep their type arguments as json.RawMessage
+	if tag, err := types.ParseTypeTag(t); err == nil && tag.Type == types.StructTag {
+		if tag.Struct.Address.String()+"::"+tag.Struct.Module == m.module {
+			if name, ok := m.structs[tag.Struct.Name]; ok {
+				return name
+			}
+		}
+	}
+	return "json.RawMessage"
+}
+
+func goUint(t string) string {
+	if t == "bool" {
+		return t
+	}
+	return "uint" + t[1:]
+}
+
+func elemType(t, prefix string) string {
+	return strings.TrimSpace(t[len(prefix) : len(t)-1])
+}
+
+func hasAbility(abilities []string, ability string) bool {
+	for _, a := range abilities {
+		if a == ability {
+			return true
+		}
+	}
+	return false
+}
+
+// goName turns snake_case Move names into exported CamelCase Go names
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	if b.Len() == 0 || b.String()[0] >= '0' && b.String()[0] <= '9' {
+		return "X" + b.String()
+	}
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package bind
+
+import (
+	"encoding/json"
+	"errors"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"strings"
+	"testing"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+const testABI = `{
+	"address": "0x1",
+	"name": "aptos_account",
+	"friends": [],
+	"exposed_functions": [
+		{"name": "transfer", "visibility": "public", "is_entry": true, "is_view": false, "generic_type_params": [], "params": ["&signer", "address", "u64"], "return": []},
+		{"name": "transfer_coins", "visibility": "public", "is_entry": true, "is_view": false, "generic_type_params": [{"constraints": []}], "params": ["&signer", "address", "u64"], "return": []},
+		{"name": "assert_account_exists", "visibility": "public", "is_entry": false, "is_view": false, "generic_type_params": [], "params": ["address"], "return": []},
+		{"name": "can_receive_direct_coin_transfers", "visibility": "public", "is_entry": false, "is_view": true, "generic_type_params": [], "params": ["address"], "return": ["bool"]},
+		{"name": "config", "visibility": "public", "is_entry": false, "is_view": true, "generic_type_params": [], "params": [], "return": ["0x1::aptos_account::DirectTransferConfig", "u64", "0x1::option::Option<u128>"]}
+	],
+	"structs": [
+		{"name": "DirectTransferConfig", "is_native": false, "is_event": false, "abilities": ["key"], "generic_type_params": [], "fields": [{"name": "allow_arbitrary_coin_transfers", "type": "bool"}]},
+		{"name": "DirectCoinTransferConfigUpdated", "is_native": false, "is_event": true, "abilities": ["drop", "store"], "generic_type_params": [], "fields": [{"name": "new_allow_direct_transfers", "type": "bool"}, {"name": "store", "type": "0x1::object::Object<0x1::fungible_asset::FungibleStore>"}]}
+	]
+}`
+
+func TestGenerate(t *testing.T) {
+	var abi types.MoveModuleABI
+	if err := json.Unmarshal([]byte(testABI), &abi); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	code, err := Generate(&abi, "aptosaccount")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	typeCheck(t, code)
+
+	tests := []struct {
+		name string
+		want string
+		none bool
+	}{
+		{name: "package", want: "package aptosaccount"},
+		{name: "module id", want: `var ModuleId = "0x1::aptos_account"`},
+		{name: "entry", want: "func TransferPayload(arg0 types.AccountAddress, arg1 uint64) (*types.EntryFunctionPayload, error)"},
+		{name: "generic entry", want: "func TransferCoinsPayload(typeArgs []string, arg0 types.AccountAddress, arg1 uint64)"},
+		{name: "not exposed", want: "AssertAccountExists", none: true},
+		{name: "view", want: "CanReceiveDirectCoinTransfers(ctx context.Context, arg0 types.AccountAddress) (r0 bool, err error)"},
+		{name: "view struct return", want: "Config(ctx context.Context) (r0 DirectTransferConfig, r1 types.U64, r2 types.Option[types.Uint], err error)"},
+		{name: "struct", want: "AllowArbitraryCoinTransfers bool `json:\"allow_arbitrary_coin_transfers\"`"},
+		{name: "object field", want: "types.Object `json:\"store\"`"},
+		{name: "resource", want: "DirectTransferConfigResource(owner types.AccountAddress) (*DirectTransferConfig, error)"},
+		{name: "event is no resource", want: "DirectCoinTransferConfigUpdatedResource", none: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Contains(string(code), tt.want); got == tt.none {
+				t.Errorf("Generate() contains %q = %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err = Generate(&types.MoveModuleABI{}, ""); !errors.Is(err, types.ErrABINull) {
+		t.Errorf("Generate() error = %v, want %v", err, types.ErrABINull)
+	}
+}
+
+// typeCheck parses and type-checks the generated code against the packages it imports
+func typeCheck(t *testing.T, code []byte) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "bindings.go", code, 0)
+	if err != nil {
+		t.Fatalf("parser.ParseFile() error = %v\n%s", err, code)
+	}
+
+	conf := gotypes.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err = conf.Check(file.Name.Name, fset, []*ast.File{file}, nil); err != nil {
+		t.Fatalf("types.Config.Check() error = %v\n%s", err, code)
+	}
+}
+
+func TestEntryFunctionPayload(t *testing.T) {
+	payload, err := EntryFunctionPayload("0x1::aptos_account::transfer", nil, []string{"address", "u64"}, types.AccountOne, uint64(100))
+	if err != nil {
+		t.Fatalf("EntryFunctionPayload() error = %v", err)
+	}
+
+	if payload.Type != types.EntryFunctionPayloadTy || len(payload.TypeArguments) != 0 ||
+		payload.Arguments[0] != "0x1" || payload.Arguments[1] != "100" {
+		t.Errorf("EntryFunctionPayload() = %+v", payload)
+	}
+
+	if _, err = EntryFunctionPayload("0x1::aptos_account::transfer", nil, []string{"address", "u64"}, types.AccountOne); !errors.Is(err, types.ErrArgumentCount) {
+		t.Errorf("EntryFunctionPayload() error = %v, want %v", err, types.ErrArgumentCount)
+	}
+
+	if got := StructType("0x1::coin::CoinStore", []string{"0x1::aptos_coin::AptosCoin"}); got != "0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>" {
+		t.Errorf("StructType() = %v", got)
+	}
+}
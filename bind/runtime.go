@@ -0,0 +1,65 @@
+package bind
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/threeandtwo/aptclient/client"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// EntryFunctionPayload returns the payload calling function, args are coerced to the JSON of params
+// by client.CoerceArguments, generated payload constructors call it
+func EntryFunctionPayload(function string, typeArgs []string, params []string, args ...interface{}) (*types.EntryFunctionPayload, error) {
+	arguments, err := client.CoerceArguments(params, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if typeArgs == nil {
+		typeArgs = []string{}
+	}
+
+	return &types.EntryFunctionPayload{
+		Type:          types.EntryFunctionPayloadTy,
+		Function:      function,
+		TypeArguments: typeArgs,
+		Arguments:     arguments,
+	}, nil
+}
+
+// View calls the view function and decodes its values into out, generated view callers call it
+func View(ctx context.Context, c *client.AptClient, function string, typeArgs []string, params []string, args []interface{}, out ...interface{}) error {
+	arguments, err := client.CoerceArguments(params, args)
+	if err != nil {
+		return err
+	}
+	return c.ViewInto(ctx, function, typeArgs, arguments, "", out...)
+}
+
+// Resource decodes the resourceType resource of owner into out, generated resource getters call it
+func Resource(c *client.AptClient, owner types.AccountAddress, resourceType string, out interface{}) error {
+	res, err := c.AccountResourceByType(owner, resourceType, "")
+	if err != nil {
+		return err
+	}
+
+	if res == nil || res.Data == nil {
+		return types.ErrResourceTypeNull
+	}
+
+	b, err := json.Marshal(res.Data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// StructType returns name<typeArgs...>, or name for structs without type parameters
+func StructType(name string, typeArgs []string) string {
+	if len(typeArgs) == 0 {
+		return name
+	}
+	return name + "<" + strings.Join(typeArgs, ", ") + ">"
+}
@@ -0,0 +1,62 @@
+package bind
+
+import "text/template"
+
+var bindTemplate = template.Must(template.New("bind").Parse(`// Code generated by aptgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/threeandtwo/aptclient/bind"
+	"github.com/threeandtwo/aptclient/client"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+var (
+	_ = context.Background
+	_ = json.Marshal
+	_ = big.NewInt
+	_ = types.AccountOne
+)
+
+// ModuleId is the module the bindings call, change it for a deployment at another address
+var ModuleId = "{{.ModuleId}}"
+{{range .EntryFunctions}}
+// {{.GoName}}Payload calls the entry function {{.Name}}{{if .TypeParams}} with {{.TypeParams}} type arguments{{end}}
+func {{.GoName}}Payload({{if .TypeParams}}typeArgs []string, {{end}}{{range .Params}}{{.Name}} {{.GoType}}, {{end}}) (*types.EntryFunctionPayload, error) {
+	return bind.EntryFunctionPayload(ModuleId+"::{{.Name}}", {{if .TypeParams}}typeArgs{{else}}nil{{end}}, []string{ {{.MoveParams}} }{{range .Params}}, {{.Name}}{{end}})
+}
+{{end}}
+// {{.Module}} calls the view functions and reads the resources of the module
+type {{.Module}} struct {
+	c *client.AptClient
+}
+
+func New{{.Module}}(c *client.AptClient) *{{.Module}} {
+	return &{{.Module}}{c: c}
+}
+{{range .ViewFunctions}}
+// {{.GoName}} calls the view function {{.Name}}{{if .TypeParams}} with {{.TypeParams}} type arguments{{end}}
+func (m *{{$.Module}}) {{.GoName}}(ctx context.Context, {{if .TypeParams}}typeArgs []string, {{end}}{{range .Params}}{{.Name}} {{.GoType}}, {{end}}) ({{range .Returns}}{{.Name}} {{.GoType}}, {{end}}err error) {
+	err = bind.View(ctx, m.c, ModuleId+"::{{.Name}}", {{if .TypeParams}}typeArgs{{else}}nil{{end}}, []string{ {{.MoveParams}} }, []interface{}{ {{range .Params}}{{.Name}}, {{end}} }{{range .Returns}}, &{{.Name}}{{end}})
+	return
+}
+{{end}}{{range .Structs}}
+// {{.GoName}} is the struct {{.Name}}{{if .IsEvent}}, emitted as an event{{end}}
+type {{.GoName}} struct {
+{{- range .Fields}}
+	{{.GoName}} {{.GoType}} ` + "`json:\"{{.Name}}\"`" + ` // {{.MoveType}}
+{{- end}}
+}
+{{if .IsResource}}
+// {{.GoName}}Resource reads the {{.Name}} resource of owner{{if .TypeParams}}, typeArgs are its {{.TypeParams}} type arguments{{end}}
+func (m *{{$.Module}}) {{.GoName}}Resource(owner types.AccountAddress{{if .TypeParams}}, typeArgs ...string{{end}}) (*{{.GoName}}, error) {
+	out := &{{.GoName}}{}
+	err := bind.Resource(m.c, owner, bind.StructType(ModuleId+"::{{.Name}}", {{if .TypeParams}}typeArgs{{else}}nil{{end}}), out)
+	return out, err
+}
+{{end}}{{end}}`))
@@ -0,0 +1,158 @@
+package bytecode
+
+import (
+	"strings"
+
+	"github.com/threeandtwo/aptclient/bcs"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// MetadataV1Key is the key of the Aptos runtime metadata holding the view function and event attributes
+const MetadataV1Key = "aptos::metadata_v1"
+
+// kinds of the known attributes of the Aptos runtime metadata
+const (
+	attributeView  = 1
+	attributeEvent = 4
+)
+
+// ABI returns the ABI of the module like GET /accounts/{address}/module/{module}: the public, friend
+// and entry functions and every struct, view functions and events are read from the Aptos metadata
+func (m *Module) ABI() *types.MoveModuleABI {
+	structAttrs, funAttrs := m.attributes()
+
+	abi := &types.MoveModuleABI{
+		Address:          m.Address().String(),
+		Name:             m.Name(),
+		Friends:          m.Friends(),
+		ExposedFunctions: []*types.MoveFunction{},
+		Structs:          []*types.MoveStruct{},
+	}
+
+	for _, def := range m.FunctionDefs {
+		if def.Visibility == VisibilityPrivate && !def.IsEntry {
+			continue
+		}
+
+		h := m.FunctionHandles[def.Handle]
+		name := m.Identifiers[h.Name]
+		names := typeParamNames(len(h.TypeParams))
+		typeParams := make([]*types.MoveGenericTypeParam, 0, len(h.TypeParams))
+		for _, constraints := range h.TypeParams {
+			typeParams = append(typeParams, &types.MoveGenericTypeParam{Constraints: abilityNames(constraints)})
+		}
+
+		abi.ExposedFunctions = append(abi.ExposedFunctions, &types.MoveFunction{
+			Name:              name,
+			Visibility:        abiVisibility(def.Visibility),
+			IsEntry:           def.IsEntry || def.Visibility == VisibilityScript,
+			IsView:            hasAttribute(funAttrs[name], attributeView),
+			GenericTypeParams: typeParams,
+			Params:            m.abiTypes(h.Params, names),
+			Returns:           m.abiTypes(h.Returns, names),
+		})
+	}
+
+	for _, def := range m.StructDefs {
+		h := m.StructHandles[def.Handle]
+		name := m.Identifiers[h.Name]
+		names := typeParamNames(len(h.TypeParams))
+		typeParams := make([]*types.MoveGenericTypeParam, 0, len(h.TypeParams))
+		for _, p := range h.TypeParams {
+			typeParams = append(typeParams, &types.MoveGenericTypeParam{Constraints: abilityNames(p.Constraints)})
+		}
+
+		fields := make([]*types.MoveStructField, 0, len(def.Fields))
+		for _, f := range def.Fields {
+			fields = append(fields, &types.MoveStructField{Name: m.Identifiers[f.Name], Type: m.typeString(f.Type, names, true)})
+		}
+
+		abi.Structs = append(abi.Structs, &types.MoveStruct{
+			Name:              name,
+			IsNative:          def.IsNative,
+			IsEvent:           hasAttribute(structAttrs[name], attributeEvent),
+			Abilities:         abilityNames(h.Abilities),
+			GenericTypeParams: typeParams,
+			Fields:            fields,
+		})
+	}
+	return abi
+}
+
+func (m *Module) abiTypes(idx uint16, typeParams []string) []string {
+	if int(idx) >= len(m.Signatures) {
+		return []string{badIndex}
+	}
+
+	strs := make([]string, 0, len(m.Signatures[idx]))
+	for _, t := range m.Signatures[idx] {
+		strs = append(strs, m.typeString(t, typeParams, true))
+	}
+	return strs
+}
+
+// attributes decodes the struct and function attributes of the RuntimeModuleMetadataV1,
+// both are empty for modules without it or with metadata that does not decode
+func (m *Module) attributes() (map[string][]uint8, map[string][]uint8) {
+	for _, md := range m.Metadata {
+		if string(md.Key) != MetadataV1Key {
+			continue
+		}
+
+		d := bcs.NewDeserializer(md.Value)
+		// error map of abort code to its name and description
+		for n := d.Uleb128(); n > 0 && d.Error() == nil; n-- {
+			d.U64()
+			d.ReadStr()
+			d.ReadStr()
+		}
+		structAttrs, funAttrs := decodeAttributes(d), decodeAttributes(d)
+		if d.Error() != nil {
+			break
+		}
+		return structAttrs, funAttrs
+	}
+	return nil, nil
+}
+
+// decodeAttributes decodes a map of names to their attributes, only the kinds of the attributes are kept
+func decodeAttributes(d *bcs.Deserializer) map[string][]uint8 {
+	attrs := make(map[string][]uint8)
+	for n := d.Uleb128(); n > 0 && d.Error() == nil; n-- {
+		name := d.ReadStr()
+		for k := d.Uleb128(); k > 0 && d.Error() == nil; k-- {
+			attrs[name] = append(attrs[name], d.U8())
+			for a := d.Uleb128(); a > 0 && d.Error() == nil; a-- {
+				d.ReadStr()
+			}
+		}
+	}
+	return attrs
+}
+
+func hasAttribute(kinds []uint8, kind uint8) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func abiVisibility(v Visibility) string {
+	switch v {
+	case VisibilityPublic, VisibilityScript:
+		return "public"
+	case VisibilityFriend:
+		return "friend"
+	default:
+		return "private"
+	}
+}
+
+func abilityNames(a Ability) []string {
+	if a == 0 {
+		return []string{}
+	}
+	return strings.Split(a.String(), ", ")
+}
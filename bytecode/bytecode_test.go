@@ -0,0 +1,311 @@
+package bytecode
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/threeandtwo/aptclient/bcs"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// testModule builds 0x1::demo, a module with a Config resource, a public getter, an entry setter
+// calling 0x1::coin::transfer<u64>, two constants, a friend and metadata
+func testModule(version uint32, constant uint64, metadata ...*Metadata) []byte {
+	tables := map[uint8]func(s *bcs.Serializer){
+		tableModuleHandles: func(s *bcs.Serializer) {
+			s.Uleb128(0) // 0x1::demo
+			s.Uleb128(0)
+			s.Uleb128(0) // 0x1::coin
+			s.Uleb128(5)
+		},
+		tableStructHandles: func(s *bcs.Serializer) {
+			s.Uleb128(0)
+			s.Uleb128(1)
+			s.U8(uint8(AbilityKey))
+			s.Uleb128(0)
+		},
+		tableFunctionHandles: func(s *bcs.Serializer) {
+			for _, h := range []struct {
+				module, name, params, returns uint32
+				typeParams                    []Ability
+			}{{0, 3, 1, 2, nil}, {0, 4, 3, 0, nil}, {1, 6, 3, 0, []Ability{AbilityStore}}} {
+				s.Uleb128(h.module)
+				s.Uleb128(h.name)
+				s.Uleb128(h.params)
+				s.Uleb128(h.returns)
+				s.Uleb128(uint32(len(h.typeParams)))
+				for _, a := range h.typeParams {
+					s.U8(uint8(a))
+				}
+				if version >= accessSpecsVersion {
+					s.U8(0)
+				}
+			}
+		},
+		tableFunctionInst: func(s *bcs.Serializer) {
+			s.Uleb128(2)
+			s.Uleb128(4)
+		},
+		tableSignatures: func(s *bcs.Serializer) {
+			s.Uleb128(0)
+			s.Uleb128(1)
+			s.U8(uint8(TokenAddress))
+			s.Uleb128(1)
+			s.U8(uint8(TokenU64))
+			s.Uleb128(2)
+			s.U8(uint8(TokenReference))
+			s.U8(uint8(TokenSigner))
+			s.U8(uint8(TokenU64))
+			s.Uleb128(1)
+			s.U8(uint8(TokenU64))
+		},
+		tableConstantPool: func(s *bcs.Serializer) {
+			u64 := bcs.NewSerializer()
+			u64.U64(constant)
+			s.U8(uint8(TokenU64))
+			s.WriteBytes(u64.ToBytes())
+			s.U8(uint8(TokenVector))
+			s.U8(uint8(TokenU8))
+			s.WriteBytes(bcs.SerializeBytes([]byte("hi")))
+		},
+		tableIdentifiers: func(s *bcs.Serializer) {
+			for _, id := range []string{"demo", "Config", "value", "get", "set", "coin", "transfer", "account"} {
+				s.WriteStr(id)
+			}
+		},
+		tableAddressIdentifiers: func(s *bcs.Serializer) {
+			s.FixedBytes(types.AccountOne[:])
+		},
+		tableStructDefs: func(s *bcs.Serializer) {
+			s.Uleb128(0)
+			s.U8(structKindDeclared)
+			s.Uleb128(1)
+			s.Uleb128(2)
+			s.U8(uint8(TokenU64))
+		},
+		tableFunctionDefs: func(s *bcs.Serializer) {
+			// public fun get(address): u64 acquires Config
+			s.Uleb128(0)
+			s.U8(uint8(VisibilityPublic))
+			s.U8(0)
+			s.Uleb128(1)
+			s.Uleb128(0)
+			s.Uleb128(0)
+			s.Uleb128(5)
+			s.FixedBytes([]byte{byte(OpMoveLoc), 0, byte(OpImmBorrowGlobal), 0, byte(OpImmBorrowField), 0, byte(OpReadRef), byte(OpRet)})
+
+			// entry fun set(&signer, u64)
+			s.Uleb128(1)
+			s.U8(uint8(VisibilityPrivate))
+			s.U8(functionFlagEntry)
+			s.Uleb128(0)
+			s.Uleb128(0)
+			s.Uleb128(6)
+			s.FixedBytes([]byte{byte(OpMoveLoc), 0, byte(OpLdConst), 0, byte(OpCallGeneric), 0, byte(OpLdU8), 1, byte(OpPop)})
+			s.FixedBytes([]byte{byte(OpRet)})
+		},
+		tableFieldHandles: func(s *bcs.Serializer) {
+			s.Uleb128(0)
+			s.Uleb128(0)
+		},
+		tableFriendDecls: func(s *bcs.Serializer) {
+			s.Uleb128(0)
+			s.Uleb128(7)
+		},
+	}
+
+	if len(metadata) > 0 {
+		tables[tableMetadata] = func(s *bcs.Serializer) {
+			for _, md := range metadata {
+				s.WriteBytes(md.Key)
+				s.WriteBytes(md.Value)
+			}
+		}
+	}
+
+	kinds := make([]int, 0, len(tables))
+	for kind := range tables {
+		kinds = append(kinds, int(kind))
+	}
+	sort.Ints(kinds)
+
+	header, content := bcs.NewSerializer(), bcs.NewSerializer()
+	header.FixedBytes(magic)
+	header.U32(version)
+	header.Uleb128(uint32(len(kinds)))
+	for _, kind := range kinds {
+		table := bcs.NewSerializer()
+		tables[uint8(kind)](table)
+
+		header.U8(uint8(kind))
+		header.Uleb128(uint32(len(content.ToBytes())))
+		header.Uleb128(uint32(len(table.ToBytes())))
+		content.FixedBytes(table.ToBytes())
+	}
+	content.Uleb128(0)
+	return append(header.ToBytes(), content.ToBytes()...)
+}
+
+const testSummary = `module 0x1::demo // bytecode version 6
+
+// dependencies
+use 0x1::coin;
+
+// friends
+friend 0x1::account;
+
+// structs
+struct Config has key {
+    value: u64,
+}
+
+// functions
+public fun get(arg0: address): u64 acquires Config
+entry fun set(arg0: &signer, arg1: u64)
+
+// constants
+const 0: u64 = 7;
+const 1: vector<u8> = "hi";
+`
+
+const testDisassembly = `entry fun set(arg0: &signer, arg1: u64) {
+    0: MoveLoc 0
+    1: LdConst [0] 7
+    2: CallGeneric 0x1::coin::transfer<u64>
+    3: LdU8 1
+    4: Pop
+    5: Ret
+}
+`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    []byte
+		version uint32
+		wantErr error
+	}{
+		{name: "version 6", code: testModule(6, 7), version: 6},
+		{name: "flavored version 7", code: testModule(0x0A000007, 7), version: 7},
+		{name: "bad magic", code: []byte{0xA1, 0x1C, 0xEB, 0x0C, 6, 0, 0, 0}, wantErr: ErrMagic},
+		{name: "version 4", code: testModule(4, 7), wantErr: ErrVersion},
+		{name: "huge table count", code: []byte{0xA1, 0x1C, 0xEB, 0x0B, 6, 0, 0, 0, 0xFF, 0xFF, 0xFF, 0xFF, 0x0F}, wantErr: ErrTable},
+		{name: "truncated", code: testModule(6, 7)[:100], wantErr: ErrTableBounds},
+		{name: "trailing bytes", code: append(testModule(6, 7), 0), wantErr: ErrTrailing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse(tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if m.Version != tt.version || m.ID() != "0x1::demo" {
+				t.Errorf("Parse() = version %d, module %s", m.Version, m.ID())
+			}
+
+			if got := strings.Replace(m.String(), "version 7", "version 6", 1); got != testSummary {
+				t.Errorf("String() = %s, want %s", got, testSummary)
+			}
+
+			got, err := m.Disassemble("set")
+			if err != nil || got != testDisassembly {
+				t.Errorf("Disassemble() = %s, %v, want %s", got, err, testDisassembly)
+			}
+		})
+	}
+}
+
+func TestConstantValue(t *testing.T) {
+	m := &Module{}
+	vector := &SignatureToken{Kind: TokenVector, Elem: &SignatureToken{Kind: TokenU64}}
+	tests := []struct {
+		name string
+		c    *Constant
+		want string
+	}{
+		{"vector", &Constant{Type: vector, Data: []byte{1, 7, 0, 0, 0, 0, 0, 0, 0}}, "[7]"},
+		{"huge length", &Constant{Type: vector, Data: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x0F}}, "0xffffffff0f"},
+		{"string", &Constant{Type: &SignatureToken{Kind: TokenVector, Elem: &SignatureToken{Kind: TokenU8}}, Data: []byte{2, 'h', 'i'}}, `"hi"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.ConstantValue(tt.c); got != tt.want {
+				t.Errorf("ConstantValue() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old, err := Parse(testModule(6, 7))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if changes := Diff(old, old); len(changes) != 0 {
+		t.Errorf("Diff() = %v, want none", changes)
+	}
+
+	new, err := Parse(testModule(0x0A000007, 8))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var got []string
+	for _, c := range Diff(old, new) {
+		got = append(got, c.String())
+	}
+
+	want := []string{
+		"~ bytecode version: 6 => 7",
+		"~ code of function set: 6 instructions => 6 instructions",
+		"- constant: u64 = 7",
+		"+ constant: u64 = 8",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+}
+
+func TestABI(t *testing.T) {
+	// aptos::metadata_v1 with no error map, Config an event and get a view function
+	md := bcs.NewSerializer()
+	md.Uleb128(0)
+	for _, attr := range []struct {
+		name string
+		kind uint8
+	}{{"Config", attributeEvent}, {"get", attributeView}} {
+		md.Uleb128(1)
+		md.WriteStr(attr.name)
+		md.Uleb128(1)
+		md.U8(attr.kind)
+		md.Uleb128(0)
+	}
+
+	m, err := Parse(testModule(6, 7, &Metadata{Key: []byte(MetadataV1Key), Value: md.ToBytes()}))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	b, err := json.Marshal(m.ABI())
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	want := `{"address":"0x1","name":"demo","friends":["0x1::account"],"exposed_functions":[` +
+		`{"name":"get","visibility":"public","is_entry":false,"is_view":true,"generic_type_params":[],"params":["address"],"return":["u64"]},` +
+		`{"name":"set","visibility":"private","is_entry":true,"is_view":false,"generic_type_params":[],"params":["\u0026signer","u64"],"return":[]}],` +
+		`"structs":[{"name":"Config","is_native":false,"is_event":true,"abilities":["key"],"generic_type_params":[],"fields":[{"name":"value","type":"u64"}]}]}`
+	if string(b) != want {
+		t.Errorf("ABI() = %s, want %s", b, want)
+	}
+}
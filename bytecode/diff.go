@@ -0,0 +1,147 @@
+package bytecode
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"
+	Removed ChangeKind = "removed"
+	Changed ChangeKind = "changed"
+)
+
+// Change is a difference between two versions of a module, Item names what changed like
+// "function transfer" and Old and New are its declarations, empty when added or removed
+type Change struct {
+	Kind ChangeKind
+	Item string
+	Old  string
+	New  string
+}
+
+func (c *Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("+ %s: %s", c.Item, c.New)
+	case Removed:
+		return fmt.Sprintf("- %s: %s", c.Item, c.Old)
+	default:
+		return fmt.Sprintf("~ %s: %s => %s", c.Item, c.Old, c.New)
+	}
+}
+
+// Diff returns the changes from old to new of the bytecode version, dependencies, friends, structs,
+// function signatures and bodies and constants, sorted by item. Bodies are compared by their
+// disassembly, so reordered tables alone are no change
+func Diff(old, new *Module) []*Change {
+	var changes []*Change
+	if old.ID() != new.ID() {
+		changes = append(changes, &Change{Kind: Changed, Item: "module", Old: old.ID(), New: new.ID()})
+	}
+	if old.Version != new.Version {
+		changes = append(changes, &Change{Kind: Changed, Item: "bytecode version", Old: fmt.Sprint(old.Version), New: fmt.Sprint(new.Version)})
+	}
+
+	changes = append(changes, diffSets("dependency", old.Dependencies(), new.Dependencies())...)
+	changes = append(changes, diffSets("friend", old.Friends(), new.Friends())...)
+	changes = append(changes, diffSets("constant", constants(old), constants(new))...)
+
+	changes = append(changes, diffDecls("struct", structDecls(old), structDecls(new))...)
+	changes = append(changes, diffDecls("function", functionDecls(old), functionDecls(new))...)
+
+	oldBodies, newBodies := functionBodies(old), functionBodies(new)
+	for name, body := range newBodies {
+		if oldBody, ok := oldBodies[name]; ok && strings.Join(oldBody, "\n") != strings.Join(body, "\n") {
+			changes = append(changes, &Change{Kind: Changed, Item: "code of function " + name,
+				Old: fmt.Sprintf("%d instructions", len(oldBody)), New: fmt.Sprintf("%d instructions", len(body))})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Item < changes[j].Item })
+	return changes
+}
+
+func diffSets(item string, old, new []string) []*Change {
+	oldSet := make(map[string]bool, len(old))
+	for _, v := range old {
+		oldSet[v] = true
+	}
+	newSet := make(map[string]bool, len(new))
+	for _, v := range new {
+		newSet[v] = true
+	}
+
+	var changes []*Change
+	for _, v := range old {
+		if !newSet[v] {
+			changes = append(changes, &Change{Kind: Removed, Item: item, Old: v})
+		}
+	}
+	for _, v := range new {
+		if !oldSet[v] {
+			changes = append(changes, &Change{Kind: Added, Item: item, New: v})
+		}
+	}
+	return changes
+}
+
+func diffDecls(item string, old, new map[string]string) []*Change {
+	var changes []*Change
+	for name, decl := range old {
+		newDecl, ok := new[name]
+		switch {
+		case !ok:
+			changes = append(changes, &Change{Kind: Removed, Item: item + " " + name, Old: decl})
+		case newDecl != decl:
+			changes = append(changes, &Change{Kind: Changed, Item: item + " " + name, Old: decl, New: newDecl})
+		}
+	}
+	for name, decl := range new {
+		if _, ok := old[name]; !ok {
+			changes = append(changes, &Change{Kind: Added, Item: item + " " + name, New: decl})
+		}
+	}
+	return changes
+}
+
+func constants(m *Module) []string {
+	values := make([]string, 0, len(m.ConstantPool))
+	for _, c := range m.ConstantPool {
+		values = append(values, m.TypeString(c.Type, nil)+" = "+m.ConstantValue(c))
+	}
+	return values
+}
+
+func structDecls(m *Module) map[string]string {
+	decls := make(map[string]string, len(m.StructDefs))
+	for _, def := range m.StructDefs {
+		decls[m.StructName(def)] = m.StructString(def)
+	}
+	return decls
+}
+
+func functionDecls(m *Module) map[string]string {
+	decls := make(map[string]string, len(m.FunctionDefs))
+	for _, def := range m.FunctionDefs {
+		decls[m.FunctionName(def)] = m.FunctionSignature(def)
+	}
+	return decls
+}
+
+// functionBodies returns the resolved instructions of every function
+func functionBodies(m *Module) map[string][]string {
+	bodies := make(map[string][]string, len(m.FunctionDefs))
+	for _, def := range m.FunctionDefs {
+		names := typeParamNames(len(m.FunctionHandles[def.Handle].TypeParams))
+		body := make([]string, 0, len(def.Code))
+		for _, in := range def.Code {
+			body = append(body, m.InstructionString(in, names))
+		}
+		bodies[m.FunctionName(def)] = body
+	}
+	return bodies
+}
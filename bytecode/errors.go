@@ -0,0 +1,17 @@
+package bytecode
+
+import "errors"
+
+var (
+	ErrMagic       = errors.New("bytecode: bad magic, not a compiled module")
+	ErrVersion     = errors.New("bytecode: unsupported bytecode version")
+	ErrTable       = errors.New("bytecode: malformed table header")
+	ErrTableBounds = errors.New("bytecode: table out of the binary")
+	ErrIndex       = errors.New("bytecode: index out of its table")
+	ErrToken       = errors.New("bytecode: malformed signature token")
+	ErrOpcode      = errors.New("bytecode: unknown opcode")
+	ErrStructKind  = errors.New("bytecode: unknown struct field kind")
+	ErrAccessSpecs = errors.New("bytecode: access specifiers are unsupported")
+	ErrTrailing    = errors.New("bytecode: trailing bytes after the module")
+	ErrFunction    = errors.New("bytecode: function not defined by the module")
+)
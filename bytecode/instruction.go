@@ -0,0 +1,282 @@
+package bytecode
+
+import (
+	"math/big"
+)
+
+// Opcode is the serialized opcode of an instruction
+type Opcode uint8
+
+const (
+	OpPop                          Opcode = 0x01
+	OpRet                          Opcode = 0x02
+	OpBrTrue                       Opcode = 0x03
+	OpBrFalse                      Opcode = 0x04
+	OpBranch                       Opcode = 0x05
+	OpLdU64                        Opcode = 0x06
+	OpLdConst                      Opcode = 0x07
+	OpLdTrue                       Opcode = 0x08
+	OpLdFalse                      Opcode = 0x09
+	OpCopyLoc                      Opcode = 0x0A
+	OpMoveLoc                      Opcode = 0x0B
+	OpStLoc                        Opcode = 0x0C
+	OpMutBorrowLoc                 Opcode = 0x0D
+	OpImmBorrowLoc                 Opcode = 0x0E
+	OpMutBorrowField               Opcode = 0x0F
+	OpImmBorrowField               Opcode = 0x10
+	OpCall                         Opcode = 0x11
+	OpPack                         Opcode = 0x12
+	OpUnpack                       Opcode = 0x13
+	OpReadRef                      Opcode = 0x14
+	OpWriteRef                     Opcode = 0x15
+	OpAdd                          Opcode = 0x16
+	OpSub                          Opcode = 0x17
+	OpMul                          Opcode = 0x18
+	OpMod                          Opcode = 0x19
+	OpDiv                          Opcode = 0x1A
+	OpBitOr                        Opcode = 0x1B
+	OpBitAnd                       Opcode = 0x1C
+	OpXor                          Opcode = 0x1D
+	OpOr                           Opcode = 0x1E
+	OpAnd                          Opcode = 0x1F
+	OpNot                          Opcode = 0x20
+	OpEq                           Opcode = 0x21
+	OpNeq                          Opcode = 0x22
+	OpLt                           Opcode = 0x23
+	OpGt                           Opcode = 0x24
+	OpLe                           Opcode = 0x25
+	OpGe                           Opcode = 0x26
+	OpAbort                        Opcode = 0x27
+	OpNop                          Opcode = 0x28
+	OpExists                       Opcode = 0x29
+	OpMutBorrowGlobal              Opcode = 0x2A
+	OpImmBorrowGlobal              Opcode = 0x2B
+	OpMoveFrom                     Opcode = 0x2C
+	OpMoveTo                       Opcode = 0x2D
+	OpFreezeRef                    Opcode = 0x2E
+	OpShl                          Opcode = 0x2F
+	OpShr                          Opcode = 0x30
+	OpLdU8                         Opcode = 0x31
+	OpLdU128                       Opcode = 0x32
+	OpCastU8                       Opcode = 0x33
+	OpCastU64                      Opcode = 0x34
+	OpCastU128                     Opcode = 0x35
+	OpMutBorrowFieldGeneric        Opcode = 0x36
+	OpImmBorrowFieldGeneric        Opcode = 0x37
+	OpCallGeneric                  Opcode = 0x38
+	OpPackGeneric                  Opcode = 0x39
+	OpUnpackGeneric                Opcode = 0x3A
+	OpExistsGeneric                Opcode = 0x3B
+	OpMutBorrowGlobalGeneric       Opcode = 0x3C
+	OpImmBorrowGlobalGeneric       Opcode = 0x3D
+	OpMoveFromGeneric              Opcode = 0x3E
+	OpMoveToGeneric                Opcode = 0x3F
+	OpVecPack                      Opcode = 0x40
+	OpVecLen                       Opcode = 0x41
+	OpVecImmBorrow                 Opcode = 0x42
+	OpVecMutBorrow                 Opcode = 0x43
+	OpVecPushBack                  Opcode = 0x44
+	OpVecPopBack                   Opcode = 0x45
+	OpVecUnpack                    Opcode = 0x46
+	OpVecSwap                      Opcode = 0x47
+	OpLdU16                        Opcode = 0x48
+	OpLdU32                        Opcode = 0x49
+	OpLdU256                       Opcode = 0x4A
+	OpCastU16                      Opcode = 0x4B
+	OpCastU32                      Opcode = 0x4C
+	OpCastU256                     Opcode = 0x4D
+	OpImmBorrowVariantField        Opcode = 0x4E
+	OpMutBorrowVariantField        Opcode = 0x4F
+	OpImmBorrowVariantFieldGeneric Opcode = 0x50
+	OpMutBorrowVariantFieldGeneric Opcode = 0x51
+	OpPackVariant                  Opcode = 0x52
+	OpPackVariantGeneric           Opcode = 0x53
+	OpUnpackVariant                Opcode = 0x54
+	OpUnpackVariantGeneric         Opcode = 0x55
+	OpTestVariant                  Opcode = 0x56
+	OpTestVariantGeneric           Opcode = 0x57
+	OpPackClosure                  Opcode = 0x58
+	OpPackClosureGeneric           Opcode = 0x59
+	OpCallClosure                  Opcode = 0x5A
+)
+
+// operand is how the operand of an opcode is serialized
+type operand uint8
+
+const (
+	operandNone operand = iota
+	operandOffset
+	operandLocal
+	operandU8
+	operandU16
+	operandU32
+	operandU64
+	operandU128
+	operandU256
+	operandConst
+	operandFieldHandle
+	operandFieldInst
+	operandFunctionHandle
+	operandFunctionInst
+	operandStructDef
+	operandStructDefInst
+	operandSignature
+	operandVariantFieldHandle
+	operandVariantFieldInst
+	operandStructVariantHandle
+	operandStructVariantInst
+)
+
+type opcodeInfo struct {
+	name    string
+	operand operand
+	// count is set for opcodes followed by a uleb128 count, or the closure mask
+	count bool
+}
+
+var opcodes = map[Opcode]opcodeInfo{
+	OpPop:                          {name: "Pop"},
+	OpRet:                          {name: "Ret"},
+	OpBrTrue:                       {name: "BrTrue", operand: operandOffset},
+	OpBrFalse:                      {name: "BrFalse", operand: operandOffset},
+	OpBranch:                       {name: "Branch", operand: operandOffset},
+	OpLdU64:                        {name: "LdU64", operand: operandU64},
+	OpLdConst:                      {name: "LdConst", operand: operandConst},
+	OpLdTrue:                       {name: "LdTrue"},
+	OpLdFalse:                      {name: "LdFalse"},
+	OpCopyLoc:                      {name: "CopyLoc", operand: operandLocal},
+	OpMoveLoc:                      {name: "MoveLoc", operand: operandLocal},
+	OpStLoc:                        {name: "StLoc", operand: operandLocal},
+	OpMutBorrowLoc:                 {name: "MutBorrowLoc", operand: operandLocal},
+	OpImmBorrowLoc:                 {name: "ImmBorrowLoc", operand: operandLocal},
+	OpMutBorrowField:               {name: "MutBorrowField", operand: operandFieldHandle},
+	OpImmBorrowField:               {name: "ImmBorrowField", operand: operandFieldHandle},
+	OpCall:                         {name: "Call", operand: operandFunctionHandle},
+	OpPack:                         {name: "Pack", operand: operandStructDef},
+	OpUnpack:                       {name: "Unpack", operand: operandStructDef},
+	OpReadRef:                      {name: "ReadRef"},
+	OpWriteRef:                     {name: "WriteRef"},
+	OpAdd:                          {name: "Add"},
+	OpSub:                          {name: "Sub"},
+	OpMul:                          {name: "Mul"},
+	OpMod:                          {name: "Mod"},
+	OpDiv:                          {name: "Div"},
+	OpBitOr:                        {name: "BitOr"},
+	OpBitAnd:                       {name: "BitAnd"},
+	OpXor:                          {name: "Xor"},
+	OpOr:                           {name: "Or"},
+	OpAnd:                          {name: "And"},
+	OpNot:                          {name: "Not"},
+	OpEq:                           {name: "Eq"},
+	OpNeq:                          {name: "Neq"},
+	OpLt:                           {name: "Lt"},
+	OpGt:                           {name: "Gt"},
+	OpLe:                           {name: "Le"},
+	OpGe:                           {name: "Ge"},
+	OpAbort:                        {name: "Abort"},
+	OpNop:                          {name: "Nop"},
+	OpExists:                       {name: "Exists", operand: operandStructDef},
+	OpMutBorrowGlobal:              {name: "MutBorrowGlobal", operand: operandStructDef},
+	OpImmBorrowGlobal:              {name: "ImmBorrowGlobal", operand: operandStructDef},
+	OpMoveFrom:                     {name: "MoveFrom", operand: operandStructDef},
+	OpMoveTo:                       {name: "MoveTo", operand: operandStructDef},
+	OpFreezeRef:                    {name: "FreezeRef"},
+	OpShl:                          {name: "Shl"},
+	OpShr:                          {name: "Shr"},
+	OpLdU8:                         {name: "LdU8", operand: operandU8},
+	OpLdU128:                       {name: "LdU128", operand: operandU128},
+	OpCastU8:                       {name: "CastU8"},
+	OpCastU64:                      {name: "CastU64"},
+	OpCastU128:                     {name: "CastU128"},
+	OpMutBorrowFieldGeneric:        {name: "MutBorrowFieldGeneric", operand: operandFieldInst},
+	OpImmBorrowFieldGeneric:        {name: "ImmBorrowFieldGeneric", operand: operandFieldInst},
+	OpCallGeneric:                  {name: "CallGeneric", operand: operandFunctionInst},
+	OpPackGeneric:                  {name: "PackGeneric", operand: operandStructDefInst},
+	OpUnpackGeneric:                {name: "UnpackGeneric", operand: operandStructDefInst},
+	OpExistsGeneric:                {name: "ExistsGeneric", operand: operandStructDefInst},
+	OpMutBorrowGlobalGeneric:       {name: "MutBorrowGlobalGeneric", operand: operandStructDefInst},
+	OpImmBorrowGlobalGeneric:       {name: "ImmBorrowGlobalGeneric", operand: operandStructDefInst},
+	OpMoveFromGeneric:              {name: "MoveFromGeneric", operand: operandStructDefInst},
+	OpMoveToGeneric:                {name: "MoveToGeneric", operand: operandStructDefInst},
+	OpVecPack:                      {name: "VecPack", operand: operandSignature, count: true},
+	OpVecLen:                       {name: "VecLen", operand: operandSignature},
+	OpVecImmBorrow:                 {name: "VecImmBorrow", operand: operandSignature},
+	OpVecMutBorrow:                 {name: "VecMutBorrow", operand: operandSignature},
+	OpVecPushBack:                  {name: "VecPushBack", operand: operandSignature},
+	OpVecPopBack:                   {name: "VecPopBack", operand: operandSignature},
+	OpVecUnpack:                    {name: "VecUnpack", operand: operandSignature, count: true},
+	OpVecSwap:                      {name: "VecSwap", operand: operandSignature},
+	OpLdU16:                        {name: "LdU16", operand: operandU16},
+	OpLdU32:                        {name: "LdU32", operand: operandU32},
+	OpLdU256:                       {name: "LdU256", operand: operandU256},
+	OpCastU16:                      {name: "CastU16"},
+	OpCastU32:                      {name: "CastU32"},
+	OpCastU256:                     {name: "CastU256"},
+	OpImmBorrowVariantField:        {name: "ImmBorrowVariantField", operand: operandVariantFieldHandle},
+	OpMutBorrowVariantField:        {name: "MutBorrowVariantField", operand: operandVariantFieldHandle},
+	OpImmBorrowVariantFieldGeneric: {name: "ImmBorrowVariantFieldGeneric", operand: operandVariantFieldInst},
+	OpMutBorrowVariantFieldGeneric: {name: "MutBorrowVariantFieldGeneric", operand: operandVariantFieldInst},
+	OpPackVariant:                  {name: "PackVariant", operand: operandStructVariantHandle},
+	OpPackVariantGeneric:           {name: "PackVariantGeneric", operand: operandStructVariantInst},
+	OpUnpackVariant:                {name: "UnpackVariant", operand: operandStructVariantHandle},
+	OpUnpackVariantGeneric:         {name: "UnpackVariantGeneric", operand: operandStructVariantInst},
+	OpTestVariant:                  {name: "TestVariant", operand: operandStructVariantHandle},
+	OpTestVariantGeneric:           {name: "TestVariantGeneric", operand: operandStructVariantInst},
+	OpPackClosure:                  {name: "PackClosure", operand: operandFunctionHandle, count: true},
+	OpPackClosureGeneric:           {name: "PackClosureGeneric", operand: operandFunctionInst, count: true},
+	OpCallClosure:                  {name: "CallClosure", operand: operandSignature},
+}
+
+func (o Opcode) String() string {
+	if info, ok := opcodes[o]; ok {
+		return info.name
+	}
+	return "Unknown"
+}
+
+// Instruction is a decoded instruction, Index is the table index, local or branch offset of the
+// operand, Value the u8 to u64 constant or the count of VecPack, VecUnpack and the mask of
+// PackClosure and Big the u128 or u256 constant
+type Instruction struct {
+	Op    Opcode
+	Index uint16
+	Value uint64
+	Big   *big.Int
+}
+
+func (r *reader) instruction() *Instruction {
+	op := Opcode(r.U8())
+	info, ok := opcodes[op]
+	if !ok && r.Error() == nil {
+		r.SetError(ErrOpcode)
+	}
+	if r.Error() != nil {
+		return nil
+	}
+
+	in := &Instruction{Op: op}
+	switch info.operand {
+	case operandNone:
+	case operandLocal:
+		in.Index = uint16(r.U8())
+	case operandU8:
+		in.Value = uint64(r.U8())
+	case operandU16:
+		in.Value = uint64(r.U16())
+	case operandU32:
+		in.Value = uint64(r.U32())
+	case operandU64:
+		in.Value = r.U64()
+	case operandU128:
+		in.Big = r.U128()
+	case operandU256:
+		in.Big = r.U256()
+	default:
+		in.Index = r.index()
+	}
+
+	if info.count {
+		in.Value = r.uleb64()
+	}
+	return in
+}
@@ -0,0 +1,306 @@
+// Package bytecode parses the Move binary format of compiled modules, the bytecode of
+// types.AccountModule, into its tables: module, struct and function handles, signatures,
+// struct and function definitions, the constant pool and friend declarations. Module.String
+// prints a readable summary, Disassemble the code of a function, Diff the changes between
+// two versions of a module and ABI the ABI the node serves for it
+package bytecode
+
+import (
+	"strings"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// Ability is a set of Move abilities
+type Ability uint8
+
+const (
+	AbilityCopy Ability = 1 << iota
+	AbilityDrop
+	AbilityStore
+	AbilityKey
+)
+
+func (a Ability) Has(ability Ability) bool {
+	return a&ability == ability
+}
+
+// String returns the abilities like "copy, drop", empty for none
+func (a Ability) String() string {
+	var names []string
+	for _, ab := range []struct {
+		ability Ability
+		name    string
+	}{{AbilityCopy, "copy"}, {AbilityDrop, "drop"}, {AbilityStore, "store"}, {AbilityKey, "key"}} {
+		if a.Has(ab.ability) {
+			names = append(names, ab.name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// Visibility of a function definition
+type Visibility uint8
+
+const (
+	VisibilityPrivate Visibility = 0
+	VisibilityPublic  Visibility = 1
+	// VisibilityScript is the deprecated public(script), entry functions set FunctionDef.IsEntry
+	VisibilityScript Visibility = 2
+	VisibilityFriend Visibility = 3
+)
+
+func (v Visibility) String() string {
+	switch v {
+	case VisibilityPublic:
+		return "public"
+	case VisibilityScript:
+		return "public(script)"
+	case VisibilityFriend:
+		return "public(friend)"
+	default:
+		return "private"
+	}
+}
+
+type ModuleHandle struct {
+	Address uint16
+	Name    uint16
+}
+
+type StructTypeParam struct {
+	Constraints Ability
+	IsPhantom   bool
+}
+
+type StructHandle struct {
+	Module     uint16
+	Name       uint16
+	Abilities  Ability
+	TypeParams []*StructTypeParam
+}
+
+type FunctionHandle struct {
+	Module     uint16
+	Name       uint16
+	Params     uint16
+	Returns    uint16
+	TypeParams []Ability
+	// Attributes are the raw function attributes of bytecode version 8
+	Attributes []uint8
+}
+
+type FunctionInstantiation struct {
+	Handle   uint16
+	TypeArgs uint16
+}
+
+type StructDefInstantiation struct {
+	Def      uint16
+	TypeArgs uint16
+}
+
+type FieldHandle struct {
+	Owner uint16
+	Field uint16
+}
+
+type FieldInstantiation struct {
+	Handle   uint16
+	TypeArgs uint16
+}
+
+type VariantFieldHandle struct {
+	Struct   uint16
+	Variants []uint16
+	Field    uint16
+}
+
+type VariantFieldInstantiation struct {
+	Handle   uint16
+	TypeArgs uint16
+}
+
+type StructVariantHandle struct {
+	Struct  uint16
+	Variant uint16
+}
+
+type StructVariantInstantiation struct {
+	Handle   uint16
+	TypeArgs uint16
+}
+
+// TokenKind is the kind of a SignatureToken, the values are the serialized tags
+type TokenKind uint8
+
+const (
+	TokenBool         TokenKind = 0x1
+	TokenU8           TokenKind = 0x2
+	TokenU64          TokenKind = 0x3
+	TokenU128         TokenKind = 0x4
+	TokenAddress      TokenKind = 0x5
+	TokenReference    TokenKind = 0x6
+	TokenMutReference TokenKind = 0x7
+	TokenStruct       TokenKind = 0x8
+	TokenTypeParam    TokenKind = 0x9
+	TokenVector       TokenKind = 0xA
+	TokenStructInst   TokenKind = 0xB
+	TokenSigner       TokenKind = 0xC
+	TokenU16          TokenKind = 0xD
+	TokenU32          TokenKind = 0xE
+	TokenU256         TokenKind = 0xF
+	TokenFunction     TokenKind = 0x10
+)
+
+// SignatureToken is a Move type in a signature, Elem is set for vectors and references, Struct and
+// TypeArgs for structs, TypeParam for type parameters and Args, Results and Abilities for functions
+type SignatureToken struct {
+	Kind      TokenKind
+	Elem      *SignatureToken
+	Struct    uint16
+	TypeArgs  []*SignatureToken
+	TypeParam uint16
+	Args      []*SignatureToken
+	Results   []*SignatureToken
+	Abilities Ability
+}
+
+type Signature []*SignatureToken
+
+type Constant struct {
+	Type *SignatureToken
+	Data []byte
+}
+
+type FieldDef struct {
+	Name uint16
+	Type *SignatureToken
+}
+
+type VariantDef struct {
+	Name   uint16
+	Fields []*FieldDef
+}
+
+// StructDef is a struct declared by the module, native structs have no fields and enums
+// of bytecode version 7 have Variants instead of Fields
+type StructDef struct {
+	Handle   uint16
+	IsNative bool
+	Fields   []*FieldDef
+	Variants []*VariantDef
+}
+
+// FunctionDef is a function declared by the module, native functions have no code
+type FunctionDef struct {
+	Handle     uint16
+	Visibility Visibility
+	IsEntry    bool
+	IsNative   bool
+	Acquires   []uint16
+	Locals     uint16
+	Code       []*Instruction
+}
+
+type Metadata struct {
+	Key   []byte
+	Value []byte
+}
+
+// Module is a CompiledModule, every table holds the indexes into the others like the binary format
+type Module struct {
+	Version uint32
+	// Flavor is the high byte of the version, set by Aptos for bytecode version 7 on
+	Flavor uint8
+	Self   uint16
+
+	ModuleHandles               []*ModuleHandle
+	StructHandles               []*StructHandle
+	FunctionHandles             []*FunctionHandle
+	FieldHandles                []*FieldHandle
+	FriendDecls                 []*ModuleHandle
+	StructDefInstantiations     []*StructDefInstantiation
+	FunctionInstantiations      []*FunctionInstantiation
+	FieldInstantiations         []*FieldInstantiation
+	Signatures                  []Signature
+	Identifiers                 []string
+	AddressIdentifiers          []types.AccountAddress
+	ConstantPool                []*Constant
+	Metadata                    []*Metadata
+	StructDefs                  []*StructDef
+	FunctionDefs                []*FunctionDef
+	VariantFieldHandles         []*VariantFieldHandle
+	VariantFieldInstantiations  []*VariantFieldInstantiation
+	StructVariantHandles        []*StructVariantHandle
+	StructVariantInstantiations []*StructVariantInstantiation
+}
+
+// Address returns the address the module is published at
+func (m *Module) Address() types.AccountAddress {
+	return m.AddressIdentifiers[m.ModuleHandles[m.Self].Address]
+}
+
+// Name returns the module name
+func (m *Module) Name() string {
+	return m.Identifiers[m.ModuleHandles[m.Self].Name]
+}
+
+// ID returns address::name of the module
+func (m *Module) ID() string {
+	return m.moduleName(m.ModuleHandles[m.Self])
+}
+
+// Dependencies returns address::name of every other module the module uses
+func (m *Module) Dependencies() []string {
+	deps := make([]string, 0, len(m.ModuleHandles))
+	for i, h := range m.ModuleHandles {
+		if uint16(i) != m.Self {
+			deps = append(deps, m.moduleName(h))
+		}
+	}
+	return deps
+}
+
+// Friends returns address::name of every friend module
+func (m *Module) Friends() []string {
+	friends := make([]string, 0, len(m.FriendDecls))
+	for _, h := range m.FriendDecls {
+		friends = append(friends, m.moduleName(h))
+	}
+	return friends
+}
+
+// StructName returns the name of the struct definition
+func (m *Module) StructName(def *StructDef) string {
+	return m.Identifiers[m.StructHandles[def.Handle].Name]
+}
+
+// FunctionName returns the name of the function definition
+func (m *Module) FunctionName(def *FunctionDef) string {
+	return m.Identifiers[m.FunctionHandles[def.Handle].Name]
+}
+
+// Struct returns the struct definition named name
+func (m *Module) Struct(name string) (*StructDef, bool) {
+	for _, def := range m.StructDefs {
+		if m.StructName(def) == name {
+			return def, true
+		}
+	}
+	return nil, false
+}
+
+// Function returns the function definition named name
+func (m *Module) Function(name string) (*FunctionDef, bool) {
+	for _, def := range m.FunctionDefs {
+		if m.FunctionName(def) == name {
+			return def, true
+		}
+	}
+	return nil, false
+}
+
+func (m *Module) moduleName(h *ModuleHandle) string {
+	return m.AddressIdentifiers[h.Address].String() + "::" + m.Identifiers[h.Name]
+}
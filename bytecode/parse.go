@@ -0,0 +1,425 @@
+package bytecode
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/threeandtwo/aptclient/bcs"
+	"github.com/threeandtwo/aptclient/hexutil"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+var magic = []byte{0xA1, 0x1C, 0xEB, 0x0B}
+
+const (
+	MinVersion = 5
+	MaxVersion = 8
+
+	tableIndexMax      = 0xFFFF
+	tokenDepthMax      = 256
+	accessSpecsVersion = 7
+	attributesVersion  = 8
+)
+
+// table kinds of the binary format
+const (
+	tableModuleHandles        = 0x1
+	tableStructHandles        = 0x2
+	tableFunctionHandles      = 0x3
+	tableFunctionInst         = 0x4
+	tableSignatures           = 0x5
+	tableConstantPool         = 0x6
+	tableIdentifiers          = 0x7
+	tableAddressIdentifiers   = 0x8
+	tableStructDefs           = 0xA
+	tableStructDefInst        = 0xB
+	tableFunctionDefs         = 0xC
+	tableFieldHandles         = 0xD
+	tableFieldInst            = 0xE
+	tableFriendDecls          = 0xF
+	tableMetadata             = 0x10
+	tableVariantFieldHandles  = 0x11
+	tableVariantFieldInst     = 0x12
+	tableStructVariantHandles = 0x13
+	tableStructVariantInst    = 0x14
+)
+
+const (
+	structKindNative           = 0x1
+	structKindDeclared         = 0x2
+	structKindDeclaredVariants = 0x3
+
+	functionFlagNative = 0x2
+	functionFlagEntry  = 0x4
+)
+
+type tableHeader struct {
+	kind   uint8
+	offset uint32
+	length uint32
+}
+
+// ParseHex parses the 0x hex bytecode of types.AccountModule
+func ParseHex(code string) (*Module, error) {
+	b, err := hexutil.Decode(code)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+// Parse parses a compiled module of bytecode version MinVersion to MaxVersion
+func Parse(code []byte) (*Module, error) {
+	if len(code) < len(magic)+4 || !bytes.Equal(code[:len(magic)], magic) {
+		return nil, ErrMagic
+	}
+
+	r := newReader(code[len(magic):])
+	m := &Module{}
+	version := r.U32()
+	m.Version, m.Flavor = version&0x00FFFFFF, uint8(version>>24)
+	if m.Version < MinVersion || m.Version > MaxVersion {
+		return nil, fmt.Errorf("%w: %d", ErrVersion, m.Version)
+	}
+
+	// a header takes at least 3 bytes, a larger count is malformed and must not size the allocation
+	count := r.Uleb128()
+	if uint64(count)*3 > uint64(r.Remaining()) {
+		return nil, ErrTable
+	}
+	headers := make([]*tableHeader, 0, count)
+	kinds := make(map[uint8]bool)
+	for i := uint32(0); i < count && r.Error() == nil; i++ {
+		h := &tableHeader{kind: r.U8(), offset: r.Uleb128(), length: r.Uleb128()}
+		if h.kind == 0 || kinds[h.kind] {
+			return nil, ErrTable
+		}
+		kinds[h.kind] = true
+		headers = append(headers, h)
+	}
+	if r.Error() != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTable, r.Error())
+	}
+
+	// tables follow the headers back to back, the self module handle index follows the tables
+	content := code[len(code)-r.Remaining():]
+	sort.Slice(headers, func(i, j int) bool { return headers[i].offset < headers[j].offset })
+	var end uint64
+	for _, h := range headers {
+		if uint64(h.offset) != end {
+			return nil, ErrTableBounds
+		}
+		end += uint64(h.length)
+	}
+	if end > uint64(len(content)) {
+		return nil, ErrTableBounds
+	}
+
+	for _, h := range headers {
+		if err := m.parseTable(h.kind, content[h.offset:h.offset+h.length]); err != nil {
+			return nil, fmt.Errorf("table %#x: %w", h.kind, err)
+		}
+	}
+
+	r = newReader(content[end:])
+	m.Self = r.index()
+	if r.Error() != nil {
+		return nil, r.Error()
+	}
+	if r.Remaining() != 0 {
+		return nil, ErrTrailing
+	}
+
+	if err := m.check(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *Module) parseTable(kind uint8, b []byte) error {
+	r := newReader(b)
+	for r.Remaining() > 0 && r.Error() == nil {
+		switch kind {
+		case tableModuleHandles:
+			m.ModuleHandles = append(m.ModuleHandles, r.moduleHandle())
+		case tableFriendDecls:
+			m.FriendDecls = append(m.FriendDecls, r.moduleHandle())
+		case tableStructHandles:
+			m.StructHandles = append(m.StructHandles, r.structHandle())
+		case tableFunctionHandles:
+			m.FunctionHandles = append(m.FunctionHandles, r.functionHandle(m.Version))
+		case tableFunctionInst:
+			m.FunctionInstantiations = append(m.FunctionInstantiations, &FunctionInstantiation{Handle: r.index(), TypeArgs: r.index()})
+		case tableSignatures:
+			m.Signatures = append(m.Signatures, r.signature())
+		case tableConstantPool:
+			m.ConstantPool = append(m.ConstantPool, &Constant{Type: r.token(0), Data: r.ReadBytes()})
+		case tableIdentifiers:
+			m.Identifiers = append(m.Identifiers, r.ReadStr())
+		case tableAddressIdentifiers:
+			var address types.AccountAddress
+			copy(address[:], r.FixedBytes(types.AccountAddressLength))
+			m.AddressIdentifiers = append(m.AddressIdentifiers, address)
+		case tableStructDefs:
+			m.StructDefs = append(m.StructDefs, r.structDef())
+		case tableStructDefInst:
+			m.StructDefInstantiations = append(m.StructDefInstantiations, &StructDefInstantiation{Def: r.index(), TypeArgs: r.index()})
+		case tableFunctionDefs:
+			m.FunctionDefs = append(m.FunctionDefs, r.functionDef())
+		case tableFieldHandles:
+			m.FieldHandles = append(m.FieldHandles, &FieldHandle{Owner: r.index(), Field: r.index()})
+		case tableFieldInst:
+			m.FieldInstantiations = append(m.FieldInstantiations, &FieldInstantiation{Handle: r.index(), TypeArgs: r.index()})
+		case tableMetadata:
+			m.Metadata = append(m.Metadata, &Metadata{Key: r.ReadBytes(), Value: r.ReadBytes()})
+		case tableVariantFieldHandles:
+			h := &VariantFieldHandle{Struct: r.index()}
+			h.Variants = r.indexes()
+			h.Field = r.index()
+			m.VariantFieldHandles = append(m.VariantFieldHandles, h)
+		case tableVariantFieldInst:
+			m.VariantFieldInstantiations = append(m.VariantFieldInstantiations, &VariantFieldInstantiation{Handle: r.index(), TypeArgs: r.index()})
+		case tableStructVariantHandles:
+			m.StructVariantHandles = append(m.StructVariantHandles, &StructVariantHandle{Struct: r.index(), Variant: r.index()})
+		case tableStructVariantInst:
+			m.StructVariantInstantiations = append(m.StructVariantInstantiations, &StructVariantInstantiation{Handle: r.index(), TypeArgs: r.index()})
+		default:
+			return ErrTable
+		}
+	}
+	return r.Error()
+}
+
+// check validates the indexes the summary and lookups follow
+func (m *Module) check() error {
+	moduleHandle := func(h *ModuleHandle) bool {
+		return int(h.Address) < len(m.AddressIdentifiers) && int(h.Name) < len(m.Identifiers)
+	}
+
+	if int(m.Self) >= len(m.ModuleHandles) {
+		return fmt.Errorf("%w: self module handle %d", ErrIndex, m.Self)
+	}
+	for i, h := range m.ModuleHandles {
+		if !moduleHandle(h) {
+			return fmt.Errorf("%w: module handle %d", ErrIndex, i)
+		}
+	}
+	for i, h := range m.FriendDecls {
+		if !moduleHandle(h) {
+			return fmt.Errorf("%w: friend %d", ErrIndex, i)
+		}
+	}
+	for i, h := range m.StructHandles {
+		if int(h.Module) >= len(m.ModuleHandles) || int(h.Name) >= len(m.Identifiers) {
+			return fmt.Errorf("%w: struct handle %d", ErrIndex, i)
+		}
+	}
+	for i, h := range m.FunctionHandles {
+		if int(h.Module) >= len(m.ModuleHandles) || int(h.Name) >= len(m.Identifiers) ||
+			int(h.Params) >= len(m.Signatures) || int(h.Returns) >= len(m.Signatures) {
+			return fmt.Errorf("%w: function handle %d", ErrIndex, i)
+		}
+	}
+
+	for i, def := range m.StructDefs {
+		if int(def.Handle) >= len(m.StructHandles) {
+			return fmt.Errorf("%w: struct definition %d", ErrIndex, i)
+		}
+		fields := def.Fields
+		for _, v := range def.Variants {
+			if int(v.Name) >= len(m.Identifiers) {
+				return fmt.Errorf("%w: variant of struct definition %d", ErrIndex, i)
+			}
+			fields = append(fields, v.Fields...)
+		}
+		for _, f := range fields {
+			if int(f.Name) >= len(m.Identifiers) {
+				return fmt.Errorf("%w: field of struct definition %d", ErrIndex, i)
+			}
+		}
+	}
+	for i, def := range m.FunctionDefs {
+		if int(def.Handle) >= len(m.FunctionHandles) {
+			return fmt.Errorf("%w: function definition %d", ErrIndex, i)
+		}
+	}
+	return nil
+}
+
+// reader reads the binary format, a bcs.Deserializer with the table index and Move specific reads
+type reader struct {
+	*bcs.Deserializer
+}
+
+func newReader(b []byte) *reader {
+	return &reader{Deserializer: bcs.NewDeserializer(b)}
+}
+
+func (r *reader) index() uint16 {
+	v := r.Uleb128()
+	if v > tableIndexMax {
+		r.SetError(ErrIndex)
+		return 0
+	}
+	return uint16(v)
+}
+
+func (r *reader) indexes() []uint16 {
+	count := r.Uleb128()
+	var indexes []uint16
+	for i := uint32(0); i < count && r.Error() == nil; i++ {
+		indexes = append(indexes, r.index())
+	}
+	return indexes
+}
+
+// uleb64 reads the u64 uleb128 of vector counts and closure masks
+func (r *reader) uleb64() uint64 {
+	var v uint64
+	for shift := 0; shift < 64; shift += 7 {
+		b := r.U8()
+		if r.Error() != nil {
+			return 0
+		}
+		v |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return v
+		}
+	}
+	r.SetError(bcs.ErrInvalidUleb128)
+	return 0
+}
+
+func (r *reader) moduleHandle() *ModuleHandle {
+	return &ModuleHandle{Address: r.index(), Name: r.index()}
+}
+
+func (r *reader) structHandle() *StructHandle {
+	h := &StructHandle{Module: r.index(), Name: r.index(), Abilities: Ability(r.U8())}
+	count := r.Uleb128()
+	for i := uint32(0); i < count && r.Error() == nil; i++ {
+		h.TypeParams = append(h.TypeParams, &StructTypeParam{Constraints: Ability(r.U8()), IsPhantom: r.Bool()})
+	}
+	return h
+}
+
+func (r *reader) functionHandle(version uint32) *FunctionHandle {
+	h := &FunctionHandle{Module: r.index(), Name: r.index(), Params: r.index(), Returns: r.index()}
+	h.TypeParams = r.abilities()
+
+	if version >= accessSpecsVersion && r.U8() != 0 {
+		r.SetError(ErrAccessSpecs)
+	}
+
+	if version >= attributesVersion {
+		count := r.Uleb128()
+		for i := uint32(0); i < count && r.Error() == nil; i++ {
+			h.Attributes = append(h.Attributes, r.U8())
+		}
+	}
+	return h
+}
+
+func (r *reader) abilities() []Ability {
+	count := r.Uleb128()
+	var abilities []Ability
+	for i := uint32(0); i < count && r.Error() == nil; i++ {
+		abilities = append(abilities, Ability(r.U8()))
+	}
+	return abilities
+}
+
+func (r *reader) signature() Signature {
+	count := r.Uleb128()
+	var sig Signature
+	for i := uint32(0); i < count && r.Error() == nil; i++ {
+		sig = append(sig, r.token(0))
+	}
+	return sig
+}
+
+func (r *reader) token(depth int) *SignatureToken {
+	if depth > tokenDepthMax {
+		r.SetError(ErrToken)
+		return nil
+	}
+
+	t := &SignatureToken{Kind: TokenKind(r.U8())}
+	switch t.Kind {
+	case TokenBool, TokenU8, TokenU16, TokenU32, TokenU64, TokenU128, TokenU256, TokenAddress, TokenSigner:
+	case TokenReference, TokenMutReference, TokenVector:
+		t.Elem = r.token(depth + 1)
+	case TokenStruct:
+		t.Struct = r.index()
+	case TokenStructInst:
+		t.Struct = r.index()
+		count := r.Uleb128()
+		for i := uint32(0); i < count && r.Error() == nil; i++ {
+			t.TypeArgs = append(t.TypeArgs, r.token(depth+1))
+		}
+	case TokenTypeParam:
+		t.TypeParam = r.index()
+	case TokenFunction:
+		t.Args = r.tokens(depth + 1)
+		t.Results = r.tokens(depth + 1)
+		t.Abilities = Ability(r.U8())
+	default:
+		r.SetError(ErrToken)
+	}
+	return t
+}
+
+func (r *reader) tokens(depth int) []*SignatureToken {
+	count := r.Uleb128()
+	var tokens []*SignatureToken
+	for i := uint32(0); i < count && r.Error() == nil; i++ {
+		tokens = append(tokens, r.token(depth))
+	}
+	return tokens
+}
+
+func (r *reader) fields() []*FieldDef {
+	count := r.Uleb128()
+	var fields []*FieldDef
+	for i := uint32(0); i < count && r.Error() == nil; i++ {
+		fields = append(fields, &FieldDef{Name: r.index(), Type: r.token(0)})
+	}
+	return fields
+}
+
+func (r *reader) structDef() *StructDef {
+	def := &StructDef{Handle: r.index()}
+	switch r.U8() {
+	case structKindNative:
+		def.IsNative = true
+	case structKindDeclared:
+		def.Fields = r.fields()
+	case structKindDeclaredVariants:
+		count := r.Uleb128()
+		for i := uint32(0); i < count && r.Error() == nil; i++ {
+			def.Variants = append(def.Variants, &VariantDef{Name: r.index(), Fields: r.fields()})
+		}
+	default:
+		r.SetError(ErrStructKind)
+	}
+	return def
+}
+
+func (r *reader) functionDef() *FunctionDef {
+	def := &FunctionDef{Handle: r.index(), Visibility: Visibility(r.U8())}
+	flags := r.U8()
+	def.IsNative = flags&functionFlagNative != 0
+	def.IsEntry = flags&functionFlagEntry != 0
+	def.Acquires = r.indexes()
+	if def.IsNative {
+		return def
+	}
+
+	def.Locals = r.index()
+	count := r.Uleb128()
+	for i := uint32(0); i < count && r.Error() == nil; i++ {
+		def.Code = append(def.Code, r.instruction())
+	}
+	return def
+}
@@ -0,0 +1,464 @@
+package bytecode
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/threeandtwo/aptclient/bcs"
+	"github.com/threeandtwo/aptclient/hexutil"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// String returns the readable summary of the module: dependencies, friends, structs, function
+// signatures, constants and metadata keys
+func (m *Module) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "module %s // bytecode version %d\n", m.ID(), m.Version)
+
+	section := func(name string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n// %s\n", name)
+		for _, line := range lines {
+			b.WriteString(line + "\n")
+		}
+	}
+
+	var uses []string
+	for _, dep := range m.Dependencies() {
+		uses = append(uses, "use "+dep+";")
+	}
+	section("dependencies", uses)
+
+	var friends []string
+	for _, friend := range m.Friends() {
+		friends = append(friends, "friend "+friend+";")
+	}
+	section("friends", friends)
+
+	var structs []string
+	for _, def := range m.StructDefs {
+		structs = append(structs, m.StructString(def))
+	}
+	section("structs", structs)
+
+	var functions []string
+	for _, def := range m.FunctionDefs {
+		functions = append(functions, m.FunctionSignature(def))
+	}
+	section("functions", functions)
+
+	var constants []string
+	for i, c := range m.ConstantPool {
+		constants = append(constants, fmt.Sprintf("const %d: %s = %s;", i, m.TypeString(c.Type, nil), m.ConstantValue(c)))
+	}
+	section("constants", constants)
+
+	var metadata []string
+	for _, md := range m.Metadata {
+		metadata = append(metadata, fmt.Sprintf("%q: %d bytes", md.Key, len(md.Value)))
+	}
+	section("metadata", metadata)
+	return b.String()
+}
+
+// StructString returns the declaration of the struct definition
+func (m *Module) StructString(def *StructDef) string {
+	h := m.StructHandles[def.Handle]
+	typeParams := make([]string, 0, len(h.TypeParams))
+	for i, p := range h.TypeParams {
+		param := typeParamName(i)
+		if p.IsPhantom {
+			param = "phantom " + param
+		}
+		typeParams = append(typeParams, constrained(param, p.Constraints))
+	}
+
+	var b strings.Builder
+	if def.IsNative {
+		b.WriteString("native ")
+	}
+	if len(def.Variants) > 0 {
+		b.WriteString("enum ")
+	} else {
+		b.WriteString("struct ")
+	}
+	b.WriteString(m.Identifiers[h.Name] + genericList(typeParams))
+	if h.Abilities != 0 {
+		b.WriteString(" has " + h.Abilities.String())
+	}
+
+	if def.IsNative {
+		return b.String() + ";"
+	}
+
+	names := typeParamNames(len(h.TypeParams))
+	b.WriteString(" {\n")
+	for _, f := range def.Fields {
+		fmt.Fprintf(&b, "    %s: %s,\n", m.Identifiers[f.Name], m.TypeString(f.Type, names))
+	}
+	for _, v := range def.Variants {
+		fields := make([]string, 0, len(v.Fields))
+		for _, f := range v.Fields {
+			fields = append(fields, m.Identifiers[f.Name]+": "+m.TypeString(f.Type, names))
+		}
+		fmt.Fprintf(&b, "    %s { %s },\n", m.Identifiers[v.Name], strings.Join(fields, ", "))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// FunctionSignature returns the declaration of the function definition without its body
+func (m *Module) FunctionSignature(def *FunctionDef) string {
+	h := m.FunctionHandles[def.Handle]
+	typeParams := make([]string, 0, len(h.TypeParams))
+	for i, constraints := range h.TypeParams {
+		typeParams = append(typeParams, constrained(typeParamName(i), constraints))
+	}
+
+	var b strings.Builder
+	if def.IsNative {
+		b.WriteString("native ")
+	}
+	if def.Visibility != VisibilityPrivate {
+		b.WriteString(def.Visibility.String() + " ")
+	}
+	if def.IsEntry {
+		b.WriteString("entry ")
+	}
+
+	names := typeParamNames(len(h.TypeParams))
+	params := m.signatureStrings(h.Params, names)
+	for i := range params {
+		params[i] = fmt.Sprintf("arg%d: %s", i, params[i])
+	}
+	fmt.Fprintf(&b, "fun %s%s(%s)", m.Identifiers[h.Name], genericList(typeParams), strings.Join(params, ", "))
+
+	switch returns := m.signatureStrings(h.Returns, names); len(returns) {
+	case 0:
+	case 1:
+		b.WriteString(": " + returns[0])
+	default:
+		b.WriteString(": (" + strings.Join(returns, ", ") + ")")
+	}
+
+	if len(def.Acquires) > 0 {
+		acquires := make([]string, 0, len(def.Acquires))
+		for _, idx := range def.Acquires {
+			acquires = append(acquires, m.structDefName(idx))
+		}
+		b.WriteString(" acquires " + strings.Join(acquires, ", "))
+	}
+	return b.String()
+}
+
+// Disassemble returns the signature, locals and instructions of the function named name
+func (m *Module) Disassemble(name string) (string, error) {
+	def, ok := m.Function(name)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrFunction, name)
+	}
+
+	var b strings.Builder
+	b.WriteString(m.FunctionSignature(def))
+	if def.IsNative {
+		return b.String() + ";\n", nil
+	}
+
+	names := typeParamNames(len(m.FunctionHandles[def.Handle].TypeParams))
+	b.WriteString(" {\n")
+	if locals := m.signatureStrings(def.Locals, names); len(locals) > 0 {
+		b.WriteString("    locals: " + strings.Join(locals, ", ") + "\n")
+	}
+	for i, in := range def.Code {
+		fmt.Fprintf(&b, "    %d: %s\n", i, m.InstructionString(in, names))
+	}
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+// InstructionString returns the instruction with its operand resolved, typeParams name the type
+// parameters of the function it belongs to
+func (m *Module) InstructionString(in *Instruction, typeParams []string) string {
+	info := opcodes[in.Op]
+	var operand string
+	switch info.operand {
+	case operandNone:
+		return in.Op.String()
+	case operandOffset, operandLocal:
+		operand = fmt.Sprint(in.Index)
+	case operandU8, operandU16, operandU32, operandU64:
+		operand = fmt.Sprint(in.Value)
+	case operandU128, operandU256:
+		operand = in.Big.String()
+	case operandConst:
+		operand = fmt.Sprintf("[%d]", in.Index)
+		if int(in.Index) < len(m.ConstantPool) {
+			operand += " " + m.ConstantValue(m.ConstantPool[in.Index])
+		}
+	case operandFunctionHandle:
+		operand = m.functionHandleName(in.Index)
+	case operandFunctionInst:
+		operand = badIndex
+		if int(in.Index) < len(m.FunctionInstantiations) {
+			inst := m.FunctionInstantiations[in.Index]
+			operand = m.functionHandleName(inst.Handle) + genericList(m.signatureStrings(inst.TypeArgs, typeParams))
+		}
+	case operandStructDef:
+		operand = m.structDefName(in.Index)
+	case operandStructDefInst:
+		operand = badIndex
+		if int(in.Index) < len(m.StructDefInstantiations) {
+			inst := m.StructDefInstantiations[in.Index]
+			operand = m.structDefName(inst.Def) + genericList(m.signatureStrings(inst.TypeArgs, typeParams))
+		}
+	case operandFieldHandle:
+		operand = m.fieldHandleName(in.Index)
+	case operandFieldInst:
+		operand = badIndex
+		if int(in.Index) < len(m.FieldInstantiations) {
+			operand = m.fieldHandleName(m.FieldInstantiations[in.Index].Handle)
+		}
+	case operandSignature:
+		operand = strings.Join(m.signatureStrings(in.Index, typeParams), ", ")
+	default:
+		// enum variants and their fields
+		operand = fmt.Sprintf("#%d", in.Index)
+	}
+
+	if info.count {
+		operand += fmt.Sprintf(", %d", in.Value)
+	}
+	return in.Op.String() + " " + operand
+}
+
+// TypeString returns the Move type of the token, structs of other modules are fully qualified and
+// type parameters are named by typeParams
+func (m *Module) TypeString(t *SignatureToken, typeParams []string) string {
+	return m.typeString(t, typeParams, false)
+}
+
+// typeString is TypeString, qualified names the structs of the module by address::module::name too
+func (m *Module) typeString(t *SignatureToken, typeParams []string, qualified bool) string {
+	if t == nil {
+		return badIndex
+	}
+
+	switch t.Kind {
+	case TokenBool:
+		return "bool"
+	case TokenU8:
+		return "u8"
+	case TokenU16:
+		return "u16"
+	case TokenU32:
+		return "u32"
+	case TokenU64:
+		return "u64"
+	case TokenU128:
+		return "u128"
+	case TokenU256:
+		return "u256"
+	case TokenAddress:
+		return "address"
+	case TokenSigner:
+		return "signer"
+	case TokenReference:
+		return "&" + m.typeString(t.Elem, typeParams, qualified)
+	case TokenMutReference:
+		return "&mut " + m.typeString(t.Elem, typeParams, qualified)
+	case TokenVector:
+		return "vector<" + m.typeString(t.Elem, typeParams, qualified) + ">"
+	case TokenStruct, TokenStructInst:
+		args := make([]string, 0, len(t.TypeArgs))
+		for _, arg := range t.TypeArgs {
+			args = append(args, m.typeString(arg, typeParams, qualified))
+		}
+		if qualified {
+			return m.qualifiedStructName(t.Struct) + genericList(args)
+		}
+		return m.structHandleName(t.Struct) + genericList(args)
+	case TokenTypeParam:
+		if int(t.TypeParam) < len(typeParams) {
+			return typeParams[t.TypeParam]
+		}
+		return typeParamName(int(t.TypeParam))
+	case TokenFunction:
+		args := make([]string, 0, len(t.Args))
+		for _, arg := range t.Args {
+			args = append(args, m.typeString(arg, typeParams, qualified))
+		}
+		results := make([]string, 0, len(t.Results))
+		for _, result := range t.Results {
+			results = append(results, m.typeString(result, typeParams, qualified))
+		}
+		s := "|" + strings.Join(args, ", ") + "|" + strings.Join(results, ", ")
+		if t.Abilities != 0 {
+			s += " has " + t.Abilities.String()
+		}
+		return s
+	}
+	return badIndex
+}
+
+// ConstantValue returns the value of a constant of a primitive type or a vector of them,
+// vector<u8> prints as a string when it is printable UTF-8, other constants print as BCS hex
+func (m *Module) ConstantValue(c *Constant) string {
+	d := bcs.NewDeserializer(c.Data)
+	v := constantValue(d, c.Type)
+	if d.Error() != nil || d.Remaining() != 0 || v == "" {
+		return hexutil.Encode(c.Data)
+	}
+	return v
+}
+
+func constantValue(d *bcs.Deserializer, t *SignatureToken) string {
+	switch t.Kind {
+	case TokenBool:
+		return fmt.Sprint(d.Bool())
+	case TokenU8:
+		return fmt.Sprint(d.U8())
+	case TokenU16:
+		return fmt.Sprint(d.U16())
+	case TokenU32:
+		return fmt.Sprint(d.U32())
+	case TokenU64:
+		return fmt.Sprint(d.U64())
+	case TokenU128:
+		return d.U128().String()
+	case TokenU256:
+		return d.U256().String()
+	case TokenAddress:
+		var address types.AccountAddress
+		copy(address[:], d.FixedBytes(types.AccountAddressLength))
+		return "@" + address.String()
+	case TokenVector:
+		if t.Elem.Kind == TokenU8 {
+			b := d.ReadBytes()
+			if utf8.Valid(b) && isPrintable(string(b)) && len(b) > 0 {
+				return fmt.Sprintf("%q", b)
+			}
+			return hexutil.Encode(b)
+		}
+
+		count := d.Uleb128()
+		if uint64(count) > uint64(d.Remaining()) {
+			return ""
+		}
+		values := make([]string, 0, count)
+		for i := uint32(0); i < count && d.Error() == nil; i++ {
+			v := constantValue(d, t.Elem)
+			if v == "" {
+				return ""
+			}
+			values = append(values, v)
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	}
+	return ""
+}
+
+func isPrintable(s string) bool {
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+const badIndex = "<bad index>"
+
+func (m *Module) signatureStrings(idx uint16, typeParams []string) []string {
+	if int(idx) >= len(m.Signatures) {
+		return []string{badIndex}
+	}
+
+	strs := make([]string, 0, len(m.Signatures[idx]))
+	for _, t := range m.Signatures[idx] {
+		strs = append(strs, m.TypeString(t, typeParams))
+	}
+	return strs
+}
+
+// structHandleName is the name of structs of the module and address::module::name of the others
+func (m *Module) structHandleName(idx uint16) string {
+	if int(idx) >= len(m.StructHandles) {
+		return badIndex
+	}
+
+	h := m.StructHandles[idx]
+	if h.Module == m.Self {
+		return m.Identifiers[h.Name]
+	}
+	return m.moduleName(m.ModuleHandles[h.Module]) + "::" + m.Identifiers[h.Name]
+}
+
+func (m *Module) qualifiedStructName(idx uint16) string {
+	if int(idx) >= len(m.StructHandles) {
+		return badIndex
+	}
+
+	h := m.StructHandles[idx]
+	return m.moduleName(m.ModuleHandles[h.Module]) + "::" + m.Identifiers[h.Name]
+}
+
+func (m *Module) structDefName(idx uint16) string {
+	if int(idx) >= len(m.StructDefs) {
+		return badIndex
+	}
+	return m.structHandleName(m.StructDefs[idx].Handle)
+}
+
+func (m *Module) functionHandleName(idx uint16) string {
+	if int(idx) >= len(m.FunctionHandles) {
+		return badIndex
+	}
+
+	h := m.FunctionHandles[idx]
+	if h.Module == m.Self {
+		return m.Identifiers[h.Name]
+	}
+	return m.moduleName(m.ModuleHandles[h.Module]) + "::" + m.Identifiers[h.Name]
+}
+
+func (m *Module) fieldHandleName(idx uint16) string {
+	if int(idx) >= len(m.FieldHandles) {
+		return badIndex
+	}
+
+	h := m.FieldHandles[idx]
+	if int(h.Owner) >= len(m.StructDefs) || int(h.Field) >= len(m.StructDefs[h.Owner].Fields) {
+		return badIndex
+	}
+	def := m.StructDefs[h.Owner]
+	return m.structDefName(h.Owner) + "." + m.Identifiers[def.Fields[h.Field].Name]
+}
+
+func typeParamName(i int) string {
+	return fmt.Sprintf("T%d", i)
+}
+
+func typeParamNames(n int) []string {
+	names := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		names = append(names, typeParamName(i))
+	}
+	return names
+}
+
+func constrained(param string, constraints Ability) string {
+	if constraints == 0 {
+		return param
+	}
+	return param + ": " + strings.ReplaceAll(constraints.String(), ", ", " + ")
+}
+
+func genericList(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return "<" + strings.Join(args, ", ") + ">"
+}
@@ -0,0 +1,331 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/threeandtwo/aptclient/bytecode"
+	"github.com/threeandtwo/aptclient/hexutil"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// ModuleABI returns the ABI of address::moduleName, ABIs are cached per client since
+// compatible upgrades keep the signatures of public and entry functions. Upgrades may add
+// functions, ValidatePayload refetches the ABI of a function missing from the cached one
+func (a *AptClient) ModuleABI(address types.AccountAddress, moduleName string) (*types.MoveModuleABI, error) {
+	abi, _, err := a.moduleABI(address, moduleName)
+	return abi, err
+}
+
+// moduleABI is ModuleABI, it also reports whether the ABI came from the cache
+func (a *AptClient) moduleABI(address types.AccountAddress, moduleName string) (*types.MoveModuleABI, bool, error) {
+	key := abiKey(address, moduleName)
+	if abi, ok := a.abis.Load(key); ok {
+		return abi.(*types.MoveModuleABI), true, nil
+	}
+
+	module, err := a.AccountModuleById(address, moduleName, "")
+	if err != nil {
+		return nil, false, err
+	}
+
+	if module == nil || module.ABI == nil {
+		return nil, false, types.ErrABINull
+	}
+
+	a.abis.Store(key, module.ABI)
+	return module.ABI, false, nil
+}
+
+// abiKey is the cache key of the ABI of address::moduleName
+func abiKey(address types.AccountAddress, moduleName string) string {
+	return address.String() + "::" + moduleName
+}
+
+// CompiledModule returns the parsed bytecode of address::moduleName at the ledger version, the latest
+// when empty, diff two versions with bytecode.Diff
+func (a *AptClient) CompiledModule(address types.AccountAddress, moduleName, version string) (*bytecode.Module, error) {
+	module, err := a.AccountModuleById(address, moduleName, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if module == nil || module.ByteCode == "" {
+		return nil, types.ErrByteCodeNull
+	}
+	return bytecode.ParseHex(module.ByteCode)
+}
+
+// ValidatePayload checks payload against the ABI of its module and returns a copy with the arguments
+// coerced to their JSON form, see ValidateEntryFunctionPayload
+func (a *AptClient) ValidatePayload(payload *types.EntryFunctionPayload) (*types.EntryFunctionPayload, error) {
+	if payload == nil {
+		return nil, types.ErrPayloadNull
+	}
+
+	module, _, err := types.ParseFunctionId(payload.Function)
+	if err != nil {
+		return nil, err
+	}
+
+	abi, cached, err := a.moduleABI(module.Address, module.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	validated, err := ValidateEntryFunctionPayload(abi, payload)
+	if cached && errors.Is(err, types.ErrFunctionNotFound) {
+		// the module may have been upgraded with the function since its ABI was cached
+		a.abis.Delete(abiKey(module.Address, module.Name))
+		if abi, _, err = a.moduleABI(module.Address, module.Name); err != nil {
+			return nil, err
+		}
+		return ValidateEntryFunctionPayload(abi, payload)
+	}
+	return validated, err
+}
+
+// ValidateEntryFunctionPayload checks that payload calls an entry function of abi with as many type arguments
+// and arguments as it declares, leading signer params excluded, and coerces every argument to the JSON
+// of its param type: strings and Go integers for integer params, 0x hex or []byte for vector<u8>,
+// addresses in the AIP-40 form and slices of at most one value for Option<T>
+func ValidateEntryFunctionPayload(abi *types.MoveModuleABI, payload *types.EntryFunctionPayload) (*types.EntryFunctionPayload, error) {
+	if payload == nil {
+		return nil, types.ErrPayloadNull
+	}
+
+	_, name, err := types.ParseFunctionId(payload.Function)
+	if err != nil {
+		return nil, err
+	}
+
+	function, ok := abi.Function(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", types.ErrFunctionNotFound, payload.Function)
+	}
+
+	if !function.IsEntry {
+		return nil, fmt.Errorf("%w: %s", types.ErrNotEntryFunction, payload.Function)
+	}
+
+	if len(payload.TypeArguments) != len(function.GenericTypeParams) {
+		return nil, fmt.Errorf("%w: %s wants %d, got %d", types.ErrTypeArgumentCount, payload.Function,
+			len(function.GenericTypeParams), len(payload.TypeArguments))
+	}
+
+	if _, err = types.ParseTypeTags(payload.TypeArguments); err != nil {
+		return nil, err
+	}
+
+	arguments, err := CoerceArguments(function.Params, payload.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", payload.Function, err)
+	}
+
+	validated := *payload
+	validated.Arguments = arguments
+	return &validated, nil
+}
+
+// CoerceArguments converts args to the JSON of the Move types params, leading signer params are skipped,
+// see ValidateEntryFunctionPayload for the accepted values
+func CoerceArguments(params []string, args []interface{}) ([]interface{}, error) {
+	for len(params) > 0 && (params[0] == "signer" || params[0] == "&signer") {
+		params = params[1:]
+	}
+
+	if len(args) != len(params) {
+		return nil, fmt.Errorf("%w: wants %d, got %d", types.ErrArgumentCount, len(params), len(args))
+	}
+
+	arguments := make([]interface{}, 0, len(params))
+	for i, param := range params {
+		arg, err := coerceArgument(param, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d of %s: %w", i, param, err)
+		}
+		arguments = append(arguments, arg)
+	}
+	return arguments, nil
+}
+
+var uintBits = map[string]int{"u8": 8, "u16": 16, "u32": 32, "u64": 64, "u128": 128, "u256": 256}
+
+// coerceArgument converts v to the JSON of the Move type param
+func coerceArgument(param string, v interface{}) (interface{}, error) {
+	if bits, ok := uintBits[param]; ok {
+		n, err := toUint(v, bits)
+		if err != nil {
+			return nil, err
+		}
+		// u8, u16 and u32 are JSON numbers, wider integers are strings
+		if bits <= 32 {
+			return uint32(n.Uint64()), nil
+		}
+		return n.String(), nil
+	}
+
+	switch {
+	case param == "bool":
+		switch b := v.(type) {
+		case bool:
+			return b, nil
+		case string:
+			if b == "true" || b == "false" {
+				return b == "true", nil
+			}
+		}
+		return nil, types.ErrArgumentType
+	case param == "address":
+		return coerceAddress(v)
+	case param == "0x1::string::String":
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		return nil, types.ErrArgumentType
+	case param == "vector<u8>":
+		switch b := v.(type) {
+		case []byte:
+			return hexutil.Encode(b), nil
+		case types.HexBytes:
+			return hexutil.Encode(b), nil
+		case string:
+			if _, err := hexutil.Decode(b); err != nil && b != "0x" {
+				return nil, types.ErrArgumentType
+			}
+			return b, nil
+		}
+		return nil, types.ErrArgumentType
+	case strings.HasPrefix(param, "vector<"):
+		elems, err := toSlice(v)
+		if err != nil {
+			return nil, err
+		}
+
+		elemType := param[len("vector<") : len(param)-1]
+		values := make([]interface{}, 0, len(elems))
+		for _, elem := range elems {
+			value, err := coerceArgument(elemType, elem)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case strings.HasPrefix(param, "0x1::object::Object<"):
+		if obj, ok := v.(map[string]interface{}); ok {
+			v = obj["inner"]
+		}
+		return coerceAddress(v)
+	case strings.HasPrefix(param, "0x1::option::Option<"):
+		return coerceOption(param[len("0x1::option::Option<"):len(param)-1], v)
+	default:
+		// generic params and other structs are sent as given
+		return v, nil
+	}
+}
+
+func coerceAddress(v interface{}) (interface{}, error) {
+	switch address := v.(type) {
+	case string:
+		return checkAccount(address)
+	case types.AccountAddress:
+		return address.String(), nil
+	case *types.AccountAddress:
+		return address.String(), nil
+	case types.Object:
+		return address.Inner.String(), nil
+	}
+	return nil, types.ErrArgumentType
+}
+
+// coerceOption accepts nil, a slice of at most one value or {"vec": [...]}
+func coerceOption(elemType string, v interface{}) (interface{}, error) {
+	if obj, ok := v.(map[string]interface{}); ok {
+		v = obj["vec"]
+	}
+
+	var elems []interface{}
+	if v != nil {
+		var err error
+		if elems, err = toSlice(v); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(elems) > 1 {
+		return nil, types.ErrArgumentType
+	}
+
+	values := make([]interface{}, 0, len(elems))
+	for _, elem := range elems {
+		value, err := coerceArgument(elemType, elem)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return map[string]interface{}{"vec": values}, nil
+}
+
+func toSlice(v interface{}) ([]interface{}, error) {
+	rv := reflect.ValueOf(v)
+	if v == nil || rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, types.ErrArgumentType
+	}
+
+	elems := make([]interface{}, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		elems = append(elems, rv.Index(i).Interface())
+	}
+	return elems, nil
+}
+
+// toUint reads v as an unsigned integer of at most bits bits
+func toUint(v interface{}, bits int) (*big.Int, error) {
+	n := new(big.Int)
+	switch i := v.(type) {
+	case string:
+		if _, ok := n.SetString(i, 10); !ok {
+			return nil, types.ErrArgumentType
+		}
+	case json.Number:
+		if _, ok := n.SetString(i.String(), 10); !ok {
+			return nil, types.ErrArgumentType
+		}
+	case float64:
+		if i != float64(uint64(i)) {
+			return nil, types.ErrArgumentType
+		}
+		n.SetUint64(uint64(i))
+	case *big.Int:
+		n.Set(i)
+	case *types.Amount:
+		if i == nil || i.Value == nil {
+			return nil, types.ErrAmountNull
+		}
+		n.Set(i.Value)
+	case types.U64:
+		n.SetUint64(uint64(i))
+	case types.Uint:
+		n.Set(&i.Int)
+	case *types.Uint:
+		n.Set(&i.Int)
+	case uint8, uint16, uint32, uint64, uint:
+		n.SetUint64(reflect.ValueOf(i).Uint())
+	case int8, int16, int32, int64, int:
+		n.SetInt64(reflect.ValueOf(i).Int())
+	default:
+		return nil, types.ErrArgumentType
+	}
+
+	if n.Sign() < 0 || n.BitLen() > bits {
+		return nil, fmt.Errorf("%w: %s out of u%s", types.ErrArgumentType, n, strconv.Itoa(bits))
+	}
+	return n, nil
+}
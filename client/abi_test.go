@@ -0,0 +1,177 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+const testABI = `{
+	"address": "0x1",
+	"name": "aptos_account",
+	"friends": [],
+	"exposed_functions": [
+		{"name": "transfer", "visibility": "public", "is_entry": true, "is_view": false, "generic_type_params": [], "params": ["&signer", "address", "u64"], "return": []},
+		{"name": "transfer_coins", "visibility": "public", "is_entry": true, "is_view": false, "generic_type_params": [{"constraints": []}], "params": ["&signer", "address", "u64"], "return": []},
+		{"name": "batch_transfer", "visibility": "public", "is_entry": true, "is_view": false, "generic_type_params": [], "params": ["&signer", "vector<address>", "vector<u64>"], "return": []},
+		{"name": "mixed", "visibility": "public", "is_entry": true, "is_view": false, "generic_type_params": [], "params": ["u8", "bool", "vector<u8>", "0x1::option::Option<u128>", "0x1::object::Object<0x1::fungible_asset::Metadata>", "0x1::string::String"], "return": []},
+		{"name": "can_receive_direct_coin_transfers", "visibility": "public", "is_entry": false, "is_view": true, "generic_type_params": [], "params": ["address"], "return": ["bool"]}
+	],
+	"structs": [
+		{"name": "DirectTransferConfig", "is_native": false, "is_event": false, "abilities": ["key"], "generic_type_params": [], "fields": [{"name": "allow_arbitrary_coin_transfers", "type": "bool"}]}
+	]
+}`
+
+func TestValidateEntryFunctionPayload(t *testing.T) {
+	var abi types.MoveModuleABI
+	if err := json.Unmarshal([]byte(testABI), &abi); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if st, ok := abi.Struct("DirectTransferConfig"); !ok || len(st.Fields) != 1 {
+		t.Errorf("Struct() = %v, %v", st, ok)
+	}
+
+	tests := []struct {
+		name     string
+		function string
+		typeArgs []string
+		args     []interface{}
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "coerce u64 and short address",
+			function: "0x1::aptos_account::transfer",
+			args:     []interface{}{"0x0a", uint64(100)},
+			want:     `["0xa","100"]`,
+		},
+		{
+			name:     "coerce vectors",
+			function: "0x1::aptos_account::batch_transfer",
+			args:     []interface{}{[]string{"0x1", "0x2"}, []interface{}{float64(1), big.NewInt(2)}},
+			want:     `[["0x1","0x2"],["1","2"]]`,
+		},
+		{
+			name:     "coerce mixed",
+			function: "0x1::aptos_account::mixed",
+			args:     []interface{}{"255", "true", []byte{1, 2}, []string{"7"}, map[string]interface{}{"inner": "0xa"}, "name"},
+			want:     `[255,true,"0x0102",{"vec":["7"]},"0xa","name"]`,
+		},
+		{
+			name:     "none option",
+			function: "0x1::aptos_account::mixed",
+			args:     []interface{}{0, false, "0x", nil, "0xa", ""},
+			want:     `[0,false,"0x",{"vec":[]},"0xa",""]`,
+		},
+		{
+			name:     "u8 overflow",
+			function: "0x1::aptos_account::mixed",
+			args:     []interface{}{256, false, "0x", nil, "0xa", ""},
+			wantErr:  types.ErrArgumentType,
+		},
+		{
+			name:     "argument count",
+			function: "0x1::aptos_account::transfer",
+			args:     []interface{}{"0x1"},
+			wantErr:  types.ErrArgumentCount,
+		},
+		{
+			name:     "type argument count",
+			function: "0x1::aptos_account::transfer_coins",
+			args:     []interface{}{"0x1", "1"},
+			wantErr:  types.ErrTypeArgumentCount,
+		},
+		{
+			name:     "negative u64",
+			function: "0x1::aptos_account::transfer",
+			args:     []interface{}{"0x1", -1},
+			wantErr:  types.ErrArgumentType,
+		},
+		{
+			name:     "view function",
+			function: "0x1::aptos_account::can_receive_direct_coin_transfers",
+			args:     []interface{}{"0x1"},
+			wantErr:  types.ErrNotEntryFunction,
+		},
+		{
+			name:     "unknown function",
+			function: "0x1::aptos_account::unknown",
+			wantErr:  types.ErrFunctionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := &types.EntryFunctionPayload{
+				Type:          types.EntryFunctionPayloadTy,
+				Function:      tt.function,
+				TypeArguments: tt.typeArgs,
+				Arguments:     tt.args,
+			}
+
+			got, err := ValidateEntryFunctionPayload(&abi, payload)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateEntryFunctionPayload() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			b, _ := json.Marshal(got.Arguments)
+			if string(b) != tt.want {
+				t.Errorf("arguments = %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestAptClient_ValidatePayloadUpgrade(t *testing.T) {
+	// the node serves aptos_account without mixed until it is upgraded
+	var abi types.MoveModuleABI
+	if err := json.Unmarshal([]byte(testABI), &abi); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	upgraded, _ := json.Marshal(&types.AccountModule{ABI: &abi})
+	old := abi
+	old.ExposedFunctions = abi.ExposedFunctions[:3]
+	original, _ := json.Marshal(&types.AccountModule{ABI: &old})
+
+	module, requests := original, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/accounts/0x1/module/aptos_account" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		requests++
+		w.Write(module)
+	}))
+	defer server.Close()
+
+	c, err := NewAptClient(server.URL)
+	if err != nil {
+		t.Fatalf("NewAptClient() error = %v", err)
+	}
+
+	transfer := &types.EntryFunctionPayload{Function: "0x1::aptos_account::transfer", Arguments: []interface{}{"0x2", "1"}}
+	mixed := &types.EntryFunctionPayload{Function: "0x1::aptos_account::mixed", Arguments: []interface{}{1, true, "0x", []interface{}{}, "0xa", "s"}}
+	if _, err = c.ValidatePayload(transfer); err != nil || requests != 1 {
+		t.Fatalf("ValidatePayload() error = %v after %d requests", err, requests)
+	}
+	if _, err = c.ValidatePayload(mixed); !errors.Is(err, types.ErrFunctionNotFound) || requests != 2 {
+		t.Errorf("ValidatePayload() before the upgrade error = %v after %d requests", err, requests)
+	}
+
+	module = upgraded
+	if _, err = c.ValidatePayload(mixed); err != nil || requests != 3 {
+		t.Errorf("ValidatePayload() after the upgrade error = %v after %d requests", err, requests)
+	}
+	if _, err = c.ValidatePayload(mixed); err != nil || requests != 3 {
+		t.Errorf("ValidatePayload() of the cached upgrade error = %v after %d requests", err, requests)
+	}
+}
@@ -8,11 +8,11 @@ import (
 	"github.com/tyler-smith/go-bip39"
 	"strings"
 
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/mr-tron/base58"
 	"github.com/threeandtwo/aptclient/hexutil"
 	"github.com/threeandtwo/aptclient/key_manager"
 	"github.com/threeandtwo/aptclient/types"
-	"golang.org/x/crypto/sha3"
 )
 
 type AptAccount struct {
@@ -20,6 +20,9 @@ type AptAccount struct {
 	authKey string
 	prvKey  ed25519.PrivateKey
 	keyTy   types.KeyTy
+
+	curve   types.CurveTy
+	secpKey *btcec.PrivateKey
 }
 
 func NewAptAccount(key, authKey string) *AptAccount {
@@ -59,6 +62,10 @@ func (a *AptAccount) hexPrvKey2Account(prvKey string) (ed25519.PrivateKey, error
 }
 
 func (a *AptAccount) prvKey2Account(prvKey string) (*types.AptAccount, error) {
+	if a.curve == types.Secp256k1Curve {
+		return a.secp256k1PrvKey2Account(prvKey)
+	}
+
 	var privateKey ed25519.PrivateKey
 	var err error
 
@@ -81,6 +88,10 @@ func (a *AptAccount) prvKey2Account(prvKey string) (*types.AptAccount, error) {
 }
 
 func (a *AptAccount) genPrvKey() (*types.AptAccount, error) {
+	if a.curve == types.Secp256k1Curve {
+		return a.genSecp256k1PrvKey()
+	}
+
 	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
 	if err != nil {
 		return nil, err
@@ -97,6 +108,10 @@ func (a *AptAccount) genPrvKey() (*types.AptAccount, error) {
 }
 
 func (a *AptAccount) mnemonic2Account(index int) (*types.AptAccount, error) {
+	if a.curve == types.Secp256k1Curve {
+		return a.secp256k1Mnemonic2Account(index)
+	}
+
 	seed, err := bip39.NewSeedWithErrorChecking(a.key, "")
 	if err != nil {
 		return nil, err
@@ -165,25 +180,46 @@ func PrivateKey2Str(prvKey ed25519.PrivateKey) string {
 }
 
 func (a *AptAccount) publicKey() string {
+	if a.curve == types.Secp256k1Curve {
+		return hexutil.Encode(key_manager.Secp256k1PublicKey(a.secpKey))
+	}
 	return fmt.Sprint("0x", hex.EncodeToString(pubKeyBytes(a.prvKey)))
 }
 
 func (a *AptAccount) address() string {
-	hasher := sha3.New256()
-
-	hasher.Write(pubKeyBytes(a.prvKey))
-	hasher.Write([]byte("\x00"))
+	var authKey []byte
+	if a.curve == types.Secp256k1Curve {
+		authKey = key_manager.Secp256k1AuthKey(key_manager.Secp256k1PublicKey(a.secpKey))
+	} else {
+		authKey = key_manager.AuthKey(key_manager.Ed25519Scheme, pubKeyBytes(a.prvKey))
+	}
 
 	if a.authKey == "" {
-		a.authKey = fmt.Sprint("0x", hex.EncodeToString(hasher.Sum(nil)))
+		a.authKey = fmt.Sprint("0x", hex.EncodeToString(authKey))
 	}
-	return fmt.Sprint("0x", hex.EncodeToString(hasher.Sum(nil)))
+	return fmt.Sprint("0x", hex.EncodeToString(authKey))
 }
 
+// Sign signs msg by ed25519, secp256k1 accounts sign sha3-256(msg). It returns nil when secp256k1
+// signing fails, SignMessage and the transaction signers return that error instead
 func (a *AptAccount) Sign(msg []byte) []byte {
-	return ed25519.Sign(a.prvKey, msg)
+	sig, err := a.sign(msg)
+	if err != nil {
+		return nil
+	}
+	return sig
+}
+
+func (a *AptAccount) sign(msg []byte) ([]byte, error) {
+	if a.curve == types.Secp256k1Curve {
+		return key_manager.SignSecp256k1(a.secpKey, msg)
+	}
+	return ed25519.Sign(a.prvKey, msg), nil
 }
 
 func (a *AptAccount) Verify(sig, msg []byte) bool {
+	if a.curve == types.Secp256k1Curve {
+		return key_manager.VerifySecp256k1(key_manager.Secp256k1PublicKey(a.secpKey), msg, sig)
+	}
 	return ed25519.Verify(pubKeyBytes(a.prvKey), msg, sig)
 }
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/threeandtwo/aptclient/hexutil"
+	"github.com/threeandtwo/aptclient/key_manager"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// AIP-80 prefix of secp256k1 private keys
+const secp256k1PrvKeyPrefix = "secp256k1-priv-"
+
+// NewSecp256k1Account works like NewAptAccount for secp256k1 single key accounts.
+// Private keys are hex encoded with or without 0x, so keys exported from EVM wallets can be imported
+func NewSecp256k1Account(key, authKey string) *AptAccount {
+	account := NewAptAccount(key, authKey)
+	account.curve = types.Secp256k1Curve
+	return account
+}
+
+func (a *AptAccount) secp256k1PrvKey2Account(prvKey string) (*types.AptAccount, error) {
+	prvKey = strings.TrimPrefix(prvKey, secp256k1PrvKeyPrefix)
+	if !strings.HasPrefix(prvKey, "0x") && !strings.HasPrefix(prvKey, "0X") {
+		prvKey = "0x" + prvKey
+	}
+
+	res, err := hexutil.Decode(prvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := key_manager.Secp256k1KeyFromBytes(res)
+	if err != nil {
+		return nil, err
+	}
+
+	a.secpKey = privateKey
+	return a.secp256k1Account(), nil
+}
+
+func (a *AptAccount) genSecp256k1PrvKey() (*types.AptAccount, error) {
+	privateKey, err := key_manager.NewSecp256k1Key()
+	if err != nil {
+		return nil, err
+	}
+
+	a.secpKey = privateKey
+	return a.secp256k1Account(), nil
+}
+
+// secp256k1Mnemonic2Account derives m/44'/637'/index'/0/0 by BIP32
+func (a *AptAccount) secp256k1Mnemonic2Account(index int) (*types.AptAccount, error) {
+	km, err := key_manager.NewKeyManagerWithMnemonic(256, "", a.key)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := km.WithCurve(types.Secp256k1Curve).GetKey(key_manager.PurposeBIP44, key_manager.CoinTypeAPT, uint32(index), 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	a.secpKey = key.Secp256k1PrivateKey()
+	return a.secp256k1Account(), nil
+}
+
+func (a *AptAccount) secp256k1Account() *types.AptAccount {
+	return &types.AptAccount{
+		Address:      a.address(),
+		PublicKey:    a.publicKey(),
+		AuthKey:      a.authKey,
+		Curve:        types.Secp256k1Curve,
+		Secp256k1Key: a.secpKey,
+	}
+}
+
+func Secp256k1PrivateKey2Str(prvKey *btcec.PrivateKey) string {
+	return hexutil.Encode(prvKey.Serialize())
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/threeandtwo/aptclient/hexutil"
+	"github.com/threeandtwo/aptclient/key_manager"
 	"github.com/threeandtwo/aptclient/types"
 	"os"
 	"testing"
@@ -228,3 +229,98 @@ func accountByRandomKey(a *AptAccount) error {
 
 	return nil
 }
+
+func TestKeyManager_GetKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		index uint32
+	}{
+		{
+			name:  "index 0",
+			index: 0,
+		},
+		{
+			name:  "index 3",
+			index: 3,
+		},
+	}
+
+	km, err := key_manager.NewKeyManagerWithMnemonic(128, "", mnemonic)
+	if err != nil {
+		t.Fatalf("new key manager error: %s", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := km.GetKey(key_manager.PurposeBIP44, key_manager.CoinTypeAPT, 0, 0, tt.index)
+			if err != nil {
+				t.Fatalf("get key for %s error: %s", tt.name, err)
+			}
+
+			_account, err := NewAptAccount(mnemonic, "").AccountFromMnemonic(int(tt.index))
+			if err != nil {
+				t.Fatalf("account from mnemonic for %s error: %s", tt.name, err)
+			}
+
+			address, publicKey, authKey := key.EncodeApt()
+			if address != _account.Address || publicKey != _account.PublicKey || authKey != _account.AuthKey {
+				t.Errorf("%s: key manager account %s mismatched with %s", key.GetPath(), address, _account.Address)
+			}
+			t.Logf("%s: %s", key.GetPath(), address)
+		})
+	}
+}
+
+func TestNewSecp256k1Account(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		index   int
+		address string
+	}{
+		{
+			name:    "hex privateKey",
+			key:     "0xd107155adf816a0a94c6db3c9489c13ad8a1eda7ada2e558ba3bfa47c020347e",
+			address: "0x5792c985bc96f436270bd2a3c692210b09c7febb8889345ceefdbae4bacfe498",
+		},
+		{
+			name:    "evm privateKey without 0x",
+			key:     "d107155adf816a0a94c6db3c9489c13ad8a1eda7ada2e558ba3bfa47c020347e",
+			address: "0x5792c985bc96f436270bd2a3c692210b09c7febb8889345ceefdbae4bacfe498",
+		},
+		{
+			name:    "AIP-80 privateKey",
+			key:     "secp256k1-priv-0xd107155adf816a0a94c6db3c9489c13ad8a1eda7ada2e558ba3bfa47c020347e",
+			address: "0x5792c985bc96f436270bd2a3c692210b09c7febb8889345ceefdbae4bacfe498",
+		},
+		{
+			name:  "12L words",
+			key:   mnemonic,
+			index: 0,
+		},
+		{
+			name: "null key",
+			key:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			na := NewSecp256k1Account(tt.key, "")
+			_account, err := na.GetAptAccount(tt.index)
+			if err != nil {
+				t.Fatalf("secp256k1 account for %s error: %s", tt.name, err)
+			}
+
+			if tt.address != "" && _account.Address != tt.address {
+				t.Errorf("address %s mismatched with %s", _account.Address, tt.address)
+			}
+
+			msg := []byte("This is a sample message")
+			if !na.Verify(na.Sign(msg), msg) {
+				t.Errorf("signature verify error for %s", tt.name)
+			}
+			t.Logf("address: %s, privateKey: %s", _account.Address, Secp256k1PrivateKey2Str(_account.Secp256k1Key))
+		})
+	}
+}
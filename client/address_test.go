@@ -0,0 +1,91 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/threeandtwo/aptclient/bcs"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+func TestParseAccountAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "short special",
+			address: "0x1",
+			want:    "0x1",
+		},
+		{
+			name:    "long special",
+			address: "0x000000000000000000000000000000000000000000000000000000000000000a",
+			want:    "0xa",
+		},
+		{
+			name:    "no prefix upper case",
+			address: "5792C985BC96F436270BD2A3C692210B09C7FEBB8889345CEEFDBAE4BACFE498",
+			want:    "0x5792c985bc96f436270bd2a3c692210b09c7febb8889345ceefdbae4bacfe498",
+		},
+		{
+			name:    "leading zeros trimmed",
+			address: "0x92c985bc96f436270bd2a3c692210b09c7febb8889345ceefdbae4bacfe498",
+			want:    "0x0092c985bc96f436270bd2a3c692210b09c7febb8889345ceefdbae4bacfe498",
+		},
+		{
+			name:    "null",
+			address: "",
+			wantErr: types.ErrAddressNull,
+		},
+		{
+			name:    "too long",
+			address: "0x15792c985bc96f436270bd2a3c692210b09c7febb8889345ceefdbae4bacfe498",
+			wantErr: types.ErrAddressLen,
+		},
+		{
+			name:    "not hex",
+			address: "0xg1",
+			wantErr: types.ErrAddressHex,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, err := types.ParseAccountAddress(tt.address)
+			if err != tt.wantErr {
+				t.Errorf("ParseAccountAddress() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if address.String() != tt.want {
+				t.Errorf("String() = %s, want %s", address.String(), tt.want)
+			}
+
+			b, err := json.Marshal(address)
+			if err != nil {
+				t.Errorf("json.Marshal() error = %v", err)
+				return
+			}
+			var fromJson types.AccountAddress
+			if err = json.Unmarshal(b, &fromJson); err != nil || fromJson != address {
+				t.Errorf("json round trip = %s, %v", fromJson, err)
+			}
+
+			b, err = bcs.Serialize(&address)
+			if err != nil || len(b) != types.AccountAddressLength {
+				t.Errorf("bcs.Serialize() = %x, %v", b, err)
+				return
+			}
+			var fromBcs types.AccountAddress
+			if err = bcs.Deserialize(&fromBcs, b); err != nil || fromBcs != address {
+				t.Errorf("bcs round trip = %s, %v", fromBcs, err)
+			}
+		})
+	}
+}
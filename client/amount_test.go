@@ -0,0 +1,115 @@
+package client
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+func TestParseAmountRounded(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		decimals uint8
+		mode     types.RoundingMode
+		want     string
+		wantStr  string
+		wantErr  error
+	}{
+		{name: "apt", amount: "1.5", decimals: 8, want: "150000000", wantStr: "1.5"},
+		{name: "integer", amount: "42", decimals: 8, want: "4200000000", wantStr: "42"},
+		{name: "smallest unit", amount: "0.00000001", decimals: 8, want: "1", wantStr: "0.00000001"},
+		{name: "zero decimals", amount: "7", decimals: 0, want: "7", wantStr: "7"},
+		{name: "trailing zeros beyond decimals", amount: "1.500000000", decimals: 8, want: "150000000", wantStr: "1.5"},
+		{name: "above u64", amount: "1000000000000", decimals: 8, want: "100000000000000000000", wantStr: "1000000000000"},
+		{name: "precision loss", amount: "0.000000015", decimals: 8, wantErr: types.ErrAmountPrecision},
+		{name: "round down", amount: "0.000000019", decimals: 8, mode: types.RoundDown, want: "1", wantStr: "0.00000001"},
+		{name: "round up", amount: "0.000000011", decimals: 8, mode: types.RoundUp, want: "2", wantStr: "0.00000002"},
+		{name: "half up", amount: "0.5", decimals: 0, mode: types.RoundHalfUp, want: "1", wantStr: "1"},
+		{name: "half even down", amount: "2.5", decimals: 0, mode: types.RoundHalfEven, want: "2", wantStr: "2"},
+		{name: "half even up", amount: "3.5", decimals: 0, mode: types.RoundHalfEven, want: "4", wantStr: "4"},
+		{name: "half even above half", amount: "2.51", decimals: 0, mode: types.RoundHalfEven, want: "3", wantStr: "3"},
+		{name: "negative", amount: "-1", decimals: 8, wantErr: types.ErrAmountFormat},
+		{name: "no integer", amount: ".5", decimals: 8, wantErr: types.ErrAmountFormat},
+		{name: "no fraction", amount: "1.", decimals: 8, wantErr: types.ErrAmountFormat},
+		{name: "exponent", amount: "1e8", decimals: 8, wantErr: types.ErrAmountFormat},
+		{name: "empty", amount: "", decimals: 8, wantErr: types.ErrAmountFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := types.ParseAmountRounded(tt.amount, tt.decimals, tt.mode)
+			if err != tt.wantErr {
+				t.Errorf("ParseAmountRounded() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if got.Value.String() != tt.want || got.String() != tt.wantStr {
+				t.Errorf("ParseAmountRounded() = %s (%s), want %s (%s)", got.Value, got, tt.want, tt.wantStr)
+			}
+		})
+	}
+}
+
+func TestAmount_StringFixed(t *testing.T) {
+	amount := types.NewAmount(big.NewInt(5), 8)
+	if got := amount.StringFixed(); got != "0.00000005" {
+		t.Errorf("StringFixed() = %s, want 0.00000005", got)
+	}
+
+	if _, err := types.NewAmount(new(big.Int).Lsh(big.NewInt(1), 64), 8).U64(); err != types.ErrAmountOverflow {
+		t.Errorf("U64() error = %v, want %v", err, types.ErrAmountOverflow)
+	}
+
+	if got := types.NewAmount(big.NewInt(-150000005), 8).StringFixed(); got != "-1.50000005" {
+		t.Errorf("StringFixed() of a negative amount = %s, want -1.50000005", got)
+	}
+	if got := types.NewAmount(big.NewInt(-5), 8).String(); got != "-0.00000005" {
+		t.Errorf("String() of a negative amount = %s, want -0.00000005", got)
+	}
+
+	var null *types.Amount
+	if _, err := null.U64(); err != types.ErrAmountNull {
+		t.Errorf("U64() of a nil amount error = %v, want %v", err, types.ErrAmountNull)
+	}
+	if _, err := types.NewAmount(nil, 8).U64(); err != types.ErrAmountNull {
+		t.Errorf("U64() of a nil value error = %v, want %v", err, types.ErrAmountNull)
+	}
+	if _, err := CoerceArguments([]string{"u64"}, []interface{}{&types.Amount{}}); !errors.Is(err, types.ErrAmountNull) {
+		t.Errorf("CoerceArguments() of a nil value error = %v, want %v", err, types.ErrAmountNull)
+	}
+}
+
+func TestAmount_Rescale(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   *types.Amount
+		decimals uint8
+		want     string
+		wantErr  error
+	}{
+		{name: "up", amount: types.NewAmount(big.NewInt(15), 1), decimals: 8, want: "150000000"},
+		{name: "same", amount: types.NewAmount(big.NewInt(15), 8), decimals: 8, want: "15"},
+		{name: "down exact", amount: types.NewAmount(big.NewInt(150000000), 8), decimals: 6, want: "1500000"},
+		{name: "down lossy", amount: types.NewAmount(big.NewInt(150000001), 8), decimals: 6, wantErr: types.ErrAmountPrecision},
+		{name: "nil", amount: nil, decimals: 8, wantErr: types.ErrAmountNull},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.amount.Rescale(tt.decimals)
+			if err != tt.wantErr {
+				t.Errorf("Rescale() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (got.Value.String() != tt.want || got.Decimals != tt.decimals || got.String() != tt.amount.String()) {
+				t.Errorf("Rescale() = %s (%d decimals), want %s", got.Value, got.Decimals, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"crypto/ed25519"
 	"encoding/hex"
 	"encoding/json"
@@ -10,10 +11,14 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 type AptClient struct {
 	rpc string
+
+	// abis caches ModuleABI by address::module
+	abis sync.Map
 }
 
 func (a *AptClient) NodeHealth(durationSecs uint32) (string, error) {
@@ -26,9 +31,13 @@ func (a *AptClient) NodeHealth(durationSecs uint32) (string, error) {
 }
 
 func (a *AptClient) LedgerInfo() (*types.LedgerInfo, error) {
+	return a.ledgerInfo(context.Background())
+}
+
+func (a *AptClient) ledgerInfo(ctx context.Context) (*types.LedgerInfo, error) {
 	rpc := fmt.Sprintf("%s/", a.rpc)
 
-	req, err := a.connClient(rpc, nil).Request(GetTy)
+	req, err := a.connClient(rpc, nil).WithContext(ctx).Request(GetTy)
 	if err != nil {
 		return nil, err
 	}
@@ -77,12 +86,7 @@ func (a *AptClient) getBlockInfo(rpc string) (*types.Block, error) {
 	return _block, err
 }
 
-func (a *AptClient) Account(address string) (*types.Account, error) {
-	isCheck, err := checkAccount(address)
-	if isCheck {
-		return nil, err
-	}
-
+func (a *AptClient) Account(address types.AccountAddress) (*types.Account, error) {
 	rpc := fmt.Sprintf("%s/accounts/%s", a.rpc, address)
 	req, err := a.connClient(rpc, nil).Request(GetTy)
 	if err != nil {
@@ -99,51 +103,22 @@ func (a *AptClient) Account(address string) (*types.Account, error) {
 	return _account, err
 }
 
-func checkAccount(address string) (bool, error) {
-	if address == "" {
-		return true, types.ErrAddressNull
-	}
-
-	if len(address) != 66 {
-		return true, types.ErrAddressLen
-	}
-	return false, nil
-}
-
-func (a *AptClient) GetBalance(address string) (*big.Int, error) {
-	isCheck, err := checkAccount(address)
-	if isCheck {
-		return nil, err
-	}
-
-	res, err := a.AccountResourceByType(address, types.AptResourceTy, "")
+// checkAccount validates address and returns it in the AIP-40 form,
+// short addresses like 0x1 and addresses without 0x are accepted
+func checkAccount(address string) (string, error) {
+	accountAddress, err := types.ParseAccountAddress(address)
 	if err != nil {
-		return nil, err
-	}
-
-	if res.Data == nil {
-		return nil, types.ErrResourceTypeNull
-	}
-
-	if _, ok := res.Data["coin"]; !ok || reflect.TypeOf(res.Data["coin"]).Kind() != reflect.Map {
-		return nil, types.ErrParsedValue
-	}
-
-	v := res.Data["coin"].(map[string]interface{})
-
-	_v, err := strconv.ParseInt(v["value"].(string), 10, 0)
-	if err != nil {
-		return nil, err
+		return "", err
 	}
-	return big.NewInt(_v), nil
+	return accountAddress.String(), nil
 }
 
-func (a *AptClient) GetNonce(address string) (uint64, error) {
-	isCheck, err := checkAccount(address)
-	if isCheck {
-		return 0, err
-	}
+// GetBalance returns the APT of address, held as coin and as fungible asset
+func (a *AptClient) GetBalance(address types.AccountAddress) (*big.Int, error) {
+	return a.AssetBalance(context.Background(), address, types.AptCoinTy)
+}
 
+func (a *AptClient) GetNonce(address types.AccountAddress) (uint64, error) {
 	res, err := a.AccountResourceByType(address, types.AptAccountTy, "")
 	if err != nil {
 		return 0, err
@@ -161,37 +136,23 @@ func (a *AptClient) GetNonce(address string) (uint64, error) {
 	return strconv.ParseUint(nonce, 10, 64)
 }
 
-func (a *AptClient) AccountResources(address, version string) ([]*types.AccountResource, error) {
-	isCheck, err := checkAccount(address)
-	if isCheck {
-		return nil, err
-	}
-
-	rpc := ""
-	if version == "" {
-		rpc = fmt.Sprintf("%s/accounts/%s/resources", a.rpc, address)
-	} else {
-		rpc = fmt.Sprintf("%s/accounts/%s/resources?version=%s", a.rpc, address, version)
-	}
-
-	var _as []*types.AccountResource
-	req, err := a.connClient(rpc, nil).Request(GetTy)
-	if err != nil {
-		return nil, err
-	}
-
-	hasE, errDesc := hasExceptionForResp(req)
-	if hasE {
-		return nil, fmt.Errorf(errDesc)
-	}
+// AccountResources returns every resource of address, following the cursor across pages
+func (a *AptClient) AccountResources(address types.AccountAddress, version string) ([]*types.AccountResource, error) {
+	var resources []*types.AccountResource
+	err := a.WalkAccountResources(context.Background(), address, version, 0, func(page []*types.AccountResource) error {
+		resources = append(resources, page...)
+		return nil
+	})
+	return resources, err
+}
 
-	err = json.Unmarshal([]byte(req), &_as)
-	return _as, err
+func (a *AptClient) AccountResourceByType(address types.AccountAddress, resourceType, version string) (*types.AccountResource, error) {
+	return a.accountResource(context.Background(), address.String(), resourceType, version)
 }
 
-func (a *AptClient) AccountResourceByType(address, resourceType, version string) (*types.AccountResource, error) {
-	isCheck, err := checkAccount(address)
-	if isCheck {
+func (a *AptClient) accountResource(ctx context.Context, address, resourceType, version string) (*types.AccountResource, error) {
+	address, err := checkAccount(address)
+	if err != nil {
 		return nil, err
 	}
 
@@ -203,57 +164,34 @@ func (a *AptClient) AccountResourceByType(address, resourceType, version string)
 	if version == "" {
 		rpc = fmt.Sprintf("%s/accounts/%s/resource/%s", a.rpc, address, resourceType)
 	} else {
-		rpc = fmt.Sprintf("%s/accounts/%s/resource/%s?version=%s", a.rpc, address, resourceType, version)
+		rpc = fmt.Sprintf("%s/accounts/%s/resource/%s?ledger_version=%s", a.rpc, address, resourceType, version)
 	}
 
 	var _as *types.AccountResource
-	req, err := a.connClient(rpc, nil).Request(GetTy)
+	req, err := a.connClient(rpc, nil).WithContext(ctx).Request(GetTy)
 	if err != nil {
 		return nil, err
 	}
 
-	hasE, errDesc := hasExceptionForResp(req)
-	if hasE {
-		return nil, fmt.Errorf(errDesc)
+	if err = respErr(req); err != nil {
+		return nil, err
 	}
 
 	err = json.Unmarshal([]byte(req), &_as)
 	return _as, err
 }
 
-func (a *AptClient) AccountModules(address, version string) ([]*types.AccountModule, error) {
-	isCheck, err := checkAccount(address)
-	if isCheck {
-		return nil, err
-	}
-	rpc := ""
-	if version == "" {
-		rpc = fmt.Sprintf("%s/accounts/%s/modules", a.rpc, address)
-	} else {
-		rpc = fmt.Sprintf("%s/accounts/%s/modules?version=%s", a.rpc, address, version)
-	}
-
-	var _am []*types.AccountModule
-	req, err := a.connClient(rpc, nil).Request(GetTy)
-	if err != nil {
-		return nil, err
-	}
-
-	hasE, errDesc := hasExceptionForResp(req)
-	if hasE {
-		return nil, fmt.Errorf(errDesc)
-	}
-
-	err = json.Unmarshal([]byte(req), &_am)
-	return _am, err
+// AccountModules returns every module of address, following the cursor across pages
+func (a *AptClient) AccountModules(address types.AccountAddress, version string) ([]*types.AccountModule, error) {
+	var modules []*types.AccountModule
+	err := a.WalkAccountModules(context.Background(), address, version, 0, func(page []*types.AccountModule) error {
+		modules = append(modules, page...)
+		return nil
+	})
+	return modules, err
 }
 
-func (a *AptClient) AccountModuleById(address, moduleName, version string) (*types.AccountModule, error) {
-	isCheck, err := checkAccount(address)
-	if isCheck {
-		return nil, err
-	}
-
+func (a *AptClient) AccountModuleById(address types.AccountAddress, moduleName, version string) (*types.AccountModule, error) {
 	if moduleName == "" {
 		return nil, types.ErrModuleIdNull
 	}
@@ -262,7 +200,7 @@ func (a *AptClient) AccountModuleById(address, moduleName, version string) (*typ
 	if version == "" {
 		rpc = fmt.Sprintf("%s/accounts/%s/module/%s", a.rpc, address, moduleName)
 	} else {
-		rpc = fmt.Sprintf("%s/accounts/%s/module/%s?version=%s", a.rpc, address, moduleName, version)
+		rpc = fmt.Sprintf("%s/accounts/%s/module/%s?ledger_version=%s", a.rpc, address, moduleName, version)
 	}
 
 	var _am *types.AccountModule
@@ -280,59 +218,31 @@ func (a *AptClient) AccountModuleById(address, moduleName, version string) (*typ
 	return _am, err
 }
 
+// Transactions returns limit ledger transactions from version start, limit defaults to 25
 func (a *AptClient) Transactions(limit uint16, start uint64) ([]*types.Transaction, error) {
-	if limit <= 0 {
-		limit = 25
-	}
-
-	if start <= 0 {
-		start = 1
-	}
-
-	rpc := fmt.Sprintf("%s/transactions?limit=%d&start=%d", a.rpc, limit, start)
-
-	var txs []*types.Transaction
-	req, err := a.connClient(rpc, nil).Request(GetTy)
-	if err != nil {
-		return nil, err
-	}
-
-	hasE, errDesc := hasExceptionForResp(req)
-	if hasE {
-		return nil, fmt.Errorf(errDesc)
-	}
+	return a.transactions(context.Background(), fmt.Sprintf("%s/transactions", a.rpc), limit, start)
+}
 
-	err = json.Unmarshal([]byte(req), &txs)
-	return txs, err
+// TransactionsByAccount returns limit transactions sent by address from sequence number start, limit defaults to 25
+func (a *AptClient) TransactionsByAccount(address types.AccountAddress, limit uint16, start uint64) ([]*types.Transaction, error) {
+	return a.transactions(context.Background(), fmt.Sprintf("%s/accounts/%s/transactions", a.rpc, address), limit, start)
 }
 
-func (a *AptClient) TransactionsByAccount(address string, limit uint16, start uint64) ([]*types.Transaction, error) {
+func (a *AptClient) transactions(ctx context.Context, rpc string, limit uint16, start uint64) ([]*types.Transaction, error) {
 	if limit <= 0 {
 		limit = 25
 	}
 
-	if start <= 0 {
-		start = 1
-	}
-
-	isCheck, err := checkAccount(address)
-	if isCheck {
-		return nil, err
-	}
-
-	rpc := fmt.Sprintf("%s/accounts/%s/transactions?limit=%d&start=%d", a.rpc, address, limit, start)
-
-	var txs []*types.Transaction
-	req, err := a.connClient(rpc, nil).Request(GetTy)
+	req, err := a.connClient(fmt.Sprintf("%s?limit=%d&start=%d", rpc, limit, start), nil).WithContext(ctx).Request(GetTy)
 	if err != nil {
 		return nil, err
 	}
 
-	hasE, errDesc := hasExceptionForResp(req)
-	if hasE {
-		return nil, fmt.Errorf(errDesc)
+	if err = respErr(req); err != nil {
+		return nil, err
 	}
 
+	var txs []*types.Transaction
 	err = json.Unmarshal([]byte(req), &txs)
 	return txs, err
 }
@@ -358,9 +268,8 @@ func (a *AptClient) transaction(rpc string) (*types.Transaction, error) {
 		return nil, err
 	}
 
-	hasE, errDesc := hasExceptionForResp(req)
-	if hasE {
-		return nil, fmt.Errorf(errDesc)
+	if err = respErr(req); err != nil {
+		return nil, err
 	}
 
 	err = json.Unmarshal([]byte(req), &tx)
@@ -404,10 +313,9 @@ func (a *AptClient) SignTransaction(account *types.AptAccount, unsignedTx *types
 		return nil, err
 	}
 
-	sig := &types.TxSignature{
-		Type:      types.Ed25519,
-		PublicKey: account.PublicKey,
-		Signature: hex.EncodeToString(ed25519.Sign(account.PrivateKey, hexMsg)),
+	sig, err := txSignature(account, hexMsg)
+	if err != nil {
+		return nil, err
 	}
 
 	return &types.SignedTx{
@@ -416,6 +324,20 @@ func (a *AptClient) SignTransaction(account *types.AptAccount, unsignedTx *types
 	}, nil
 }
 
+// txSignature signs the signing message msg by account, ed25519 accounts sign as ed25519_signature
+// and secp256k1 accounts as a single key single_sender
+func txSignature(account *types.AptAccount, msg []byte) (*types.TxSignature, error) {
+	if account.Curve == types.Secp256k1Curve {
+		return singleKeySignature(account, msg)
+	}
+
+	return &types.TxSignature{
+		Type:      types.Ed25519,
+		PublicKey: account.PublicKey,
+		Signature: hex.EncodeToString(ed25519.Sign(account.PrivateKey, msg)),
+	}, nil
+}
+
 func (a *AptClient) SubmitTx(signedTx *types.SignedTx) (*types.Transaction, error) {
 	rpc := fmt.Sprintf("%s/transactions", a.rpc)
 	signedMap := initSigTx(signedTx)
@@ -435,9 +357,23 @@ func (a *AptClient) SubmitTx(signedTx *types.SignedTx) (*types.Transaction, erro
 	return tx, err
 }
 
+// SimulateTx simulates signedTx with its signatures zeroed, the node rejects simulations carrying a valid
+// signature. Entry function payloads are checked against their module ABI by ValidatePayload and sent
+// with the coerced arguments
 func (a *AptClient) SimulateTx(signedTx *types.SignedTx) ([]*types.SimulateTx, error) {
 	rpc := fmt.Sprintf("%s/transactions/simulate", a.rpc)
 	signedMap := initSigTx(signedTx)
+	if signedTx.Signature != nil {
+		signedMap["signature"] = signedTx.Signature.ForSimulation()
+	}
+
+	if payload, ok := signedTx.Payload.(*types.EntryFunctionPayload); ok {
+		validated, err := a.ValidatePayload(payload)
+		if err != nil {
+			return nil, err
+		}
+		signedMap["payload"] = validated
+	}
 
 	var tx []*types.SimulateTx
 	req, err := a.connClient(rpc, signedMap).Request(PostTy)
@@ -519,42 +455,32 @@ func (a *AptClient) GetEventsByKey(key string, limit uint16, start uint64) ([]*t
 	return events, err
 }
 
-func (a *AptClient) GetEventsByCreationNumber(address, creationNumber string, limit, start uint64) ([]*types.Event, error) {
-	if address == "" || creationNumber == "" {
-		return nil, fmt.Errorf("address | handle | fieldName is null, plz check it")
+func (a *AptClient) GetEventsByCreationNumber(address types.AccountAddress, creationNumber string, limit, start uint64) ([]*types.Event, error) {
+	if creationNumber == "" {
+		return nil, fmt.Errorf("creationNumber is null, plz check it")
 	}
 
 	rpc := fmt.Sprintf("%s/accounts/%s/events/%s?limit=%d&start=%d", a.rpc, address, creationNumber, limit, start)
-	req, err := a.connClient(rpc, nil).Request(GetTy)
-	if err != nil {
-		return nil, err
-	}
-
-	hasE, errDesc := hasExceptionForResp(req)
-	if hasE {
-		return nil, fmt.Errorf(errDesc)
-	}
-
-	var events []*types.Event
-	err = json.Unmarshal([]byte(req), &events)
-	return events, err
-
+	return a.events(context.Background(), rpc)
 }
 
-func (a *AptClient) GetEventsByHandle(address, handle, fieldName string, limit uint16, start uint64) ([]*types.Event, error) {
-	if address == "" || handle == "" || fieldName == "" {
-		return nil, fmt.Errorf("address | handle | fieldName is null, plz check it")
+func (a *AptClient) GetEventsByHandle(address types.AccountAddress, handle, fieldName string, limit uint16, start uint64) ([]*types.Event, error) {
+	if handle == "" || fieldName == "" {
+		return nil, fmt.Errorf("handle | fieldName is null, plz check it")
 	}
 
 	rpc := fmt.Sprintf("%s/accounts/%s/events/%s/%s?limit=%d&start=%d", a.rpc, address, handle, fieldName, limit, start)
-	req, err := a.connClient(rpc, nil).Request(GetTy)
+	return a.events(context.Background(), rpc)
+}
+
+func (a *AptClient) events(ctx context.Context, rpc string) ([]*types.Event, error) {
+	req, err := a.connClient(rpc, nil).WithContext(ctx).Request(GetTy)
 	if err != nil {
 		return nil, err
 	}
 
-	hasE, errDesc := hasExceptionForResp(req)
-	if hasE {
-		return nil, fmt.Errorf(errDesc)
+	if err = respErr(req); err != nil {
+		return nil, err
 	}
 
 	var events []*types.Event
@@ -604,6 +530,34 @@ func hasExceptionForResp(msg string) (bool, string) {
 	return true, errMsg
 }
 
+// respErr is hasExceptionForResp as an error, not found responses wrap
+// types.ErrResourceNotFound, types.ErrTableItemNotFound and types.ErrTxNotFound,
+// pruned versions types.ErrVersionPruned and versions past the head types.ErrVersionAhead
+func respErr(msg string) error {
+	hasE, errDesc := hasExceptionForResp(msg)
+	if !hasE {
+		return nil
+	}
+
+	exMsg := &types.ExceptionMsg{}
+	_ = json.Unmarshal([]byte(msg), exMsg)
+	switch exMsg.Code {
+	case types.ResourceNotFoundCode:
+		return fmt.Errorf("%w: %s", types.ErrResourceNotFound, exMsg.Message)
+	case types.TableItemNotFoundCode:
+		return fmt.Errorf("%w: %s", types.ErrTableItemNotFound, exMsg.Message)
+	case types.TransactionNotFoundCode:
+		return fmt.Errorf("%w: %s", types.ErrTxNotFound, exMsg.Message)
+	case types.VersionPrunedCode:
+		return fmt.Errorf("%w: %s", types.ErrVersionPruned, exMsg.Message)
+	case types.InvalidInputCode:
+		if strings.Contains(exMsg.Message, types.VersionAheadMsg) {
+			return fmt.Errorf("%w: %s", types.ErrVersionAhead, exMsg.Message)
+		}
+	}
+	return fmt.Errorf(errDesc)
+}
+
 func initSigTx(signedTx *types.SignedTx) map[string]interface{} {
 	signedMap := make(map[string]interface{})
 	signedMap["sender"] = signedTx.Sender
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/threeandtwo/aptclient/types"
+	"math/big"
 	"os"
 	"strconv"
 	"testing"
@@ -241,7 +242,13 @@ func TestAptClient_Account(t *testing.T) {
 				return
 
 			}
-			info, err := c.Account(tt.address)
+			address, err := types.ParseAccountAddress(tt.address)
+			if err != nil {
+				t.Logf("parse address error: %s", err)
+				return
+			}
+
+			info, err := c.Account(address)
 			if err != nil {
 				t.Logf("get account Info: %s", err)
 				return
@@ -282,7 +289,13 @@ func TestAptClient_Balance(t *testing.T) {
 				return
 
 			}
-			info, err := c.GetBalance(tt.address)
+			address, err := types.ParseAccountAddress(tt.address)
+			if err != nil {
+				t.Logf("parse address error: %s", err)
+				return
+			}
+
+			info, err := c.GetBalance(address)
 			if err != nil {
 				t.Logf("get balance Info error: %s", err)
 				return
@@ -323,7 +336,13 @@ func TestAptClient_GetNonce(t *testing.T) {
 				return
 
 			}
-			info, err := c.GetNonce(tt.address)
+			address, err := types.ParseAccountAddress(tt.address)
+			if err != nil {
+				t.Logf("parse address error: %s", err)
+				return
+			}
+
+			info, err := c.GetNonce(address)
 			if err != nil {
 				t.Logf("get balance Info error: %s", err)
 				return
@@ -368,7 +387,13 @@ func TestAptClient_AccountResources(t *testing.T) {
 				return
 			}
 
-			resources, err := c.AccountResources(tt.address, tt.version)
+			address, err := types.ParseAccountAddress(tt.address)
+			if err != nil {
+				t.Logf("parse address error: %s", err)
+				return
+			}
+
+			resources, err := c.AccountResources(address, tt.version)
 			if err != nil {
 				t.Logf("get account resource for %s error: %s", tt.name, err.Error())
 				return
@@ -426,7 +451,13 @@ func TestAptClient_AccountResourceByType(t *testing.T) {
 				return
 			}
 
-			resource, err := c.AccountResourceByType(tt.address, tt.resourceTy, tt.version)
+			address, err := types.ParseAccountAddress(tt.address)
+			if err != nil {
+				t.Logf("parse address error: %s", err)
+				return
+			}
+
+			resource, err := c.AccountResourceByType(address, tt.resourceTy, tt.version)
 			if err != nil {
 				t.Logf("get account resource for %s error: %s", tt.name, err.Error())
 				return
@@ -473,7 +504,13 @@ func TestAptClient_AccountModules(t *testing.T) {
 				return
 			}
 
-			modules, err := c.AccountModules(tt.address, tt.version)
+			address, err := types.ParseAccountAddress(tt.address)
+			if err != nil {
+				t.Logf("parse address error: %s", err)
+				return
+			}
+
+			modules, err := c.AccountModules(address, tt.version)
 			if err != nil {
 				t.Logf("get account modules for %s error: %s", tt.name, err.Error())
 				return
@@ -538,7 +575,13 @@ func TestAptClient_AccountModuleById(t *testing.T) {
 				return
 			}
 
-			module, err := c.AccountModuleById(tt.address, tt.moduleId, tt.version)
+			address, err := types.ParseAccountAddress(tt.address)
+			if err != nil {
+				t.Logf("parse address error: %s", err)
+				return
+			}
+
+			module, err := c.AccountModuleById(address, tt.moduleId, tt.version)
 			if err != nil {
 				t.Logf("account module by id for %s error: %s", tt.name, err)
 				return
@@ -656,7 +699,13 @@ func TestAptClient_TransactionsByAccount(t *testing.T) {
 				return
 			}
 
-			transactions, err := c.TransactionsByAccount(tt.address, tt.limit, tt.start)
+			address, err := types.ParseAccountAddress(tt.address)
+			if err != nil {
+				t.Logf("parse address error: %s", err)
+				return
+			}
+
+			transactions, err := c.TransactionsByAccount(address, tt.limit, tt.start)
 			if err != nil {
 				t.Logf("transaction by account for %s error: %s", tt.name, err.Error())
 				return
@@ -763,7 +812,7 @@ func TestAptClient_SignMessage(t *testing.T) {
 					Type:          "entry_function_payload",
 					Function:      "0x1::coin::transfer",
 					TypeArguments: []string{"0x1::aptos_coin::AptosCoin"},
-					Arguments:     []string{"0x6d829df49edf618de9002d16b03118f50cb0b22cb56901349720a07f6a5b10c5", strconv.FormatUint(100, 10)},
+					Arguments:     []interface{}{"0x6d829df49edf618de9002d16b03118f50cb0b22cb56901349720a07f6a5b10c5", strconv.FormatUint(100, 10)},
 				},
 			},
 		},
@@ -823,7 +872,13 @@ func TestAptClient_SendTransaction(t *testing.T) {
 				return
 			}
 
-			nonce, err := c.GetNonce(account.Address)
+			address, err := types.ParseAccountAddress(account.Address)
+			if err != nil {
+				t.Logf("parse address error: %s", err)
+				return
+			}
+
+			nonce, err := c.GetNonce(address)
 			if err != nil {
 				t.Logf("get nonce for %s error: %s", tt.name, err.Error())
 				return
@@ -893,7 +948,13 @@ func TestAptClient_SimulateTx(t *testing.T) {
 				return
 			}
 
-			nonce, err := c.GetNonce(account.Address)
+			address, err := types.ParseAccountAddress(account.Address)
+			if err != nil {
+				t.Logf("parse address error: %s", err)
+				return
+			}
+
+			nonce, err := c.GetNonce(address)
 			if err != nil {
 				t.Logf("get balance for %s error: %s", tt.name, err.Error())
 				return
@@ -966,7 +1027,13 @@ func TestAptClient_GetEventsByHandle(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			events, errEvent := c.GetEventsByHandle(tt.address, tt.handle, tt.fieldName, tt.limit, tt.start)
+			address, err := types.ParseAccountAddress(tt.address)
+			if err != nil {
+				t.Logf("parse address error: %s", err)
+				return
+			}
+
+			events, errEvent := c.GetEventsByHandle(address, tt.handle, tt.fieldName, tt.limit, tt.start)
 			if errEvent != nil {
 				t.Logf("get estimate gas price error: %s", errEvent.Error())
 				return
@@ -1020,11 +1087,9 @@ func getTransferAptParams(account *types.AptAccount, receiptAddr string, amount,
 	*types.UnsignedTx,
 	error,
 ) {
-	payload := &types.EntryFunctionPayload{
-		Type:          "entry_function_payload",
-		Function:      "0x1::coin::transfer",
-		TypeArguments: []string{"0x1::aptos_coin::AptosCoin"},
-		Arguments:     []string{receiptAddr, strconv.FormatUint(amount, 10)},
+	payload, err := TransferAPTPayload(receiptAddr, types.NewAmount(new(big.Int).SetUint64(amount), 8))
+	if err != nil {
+		return nil, err
 	}
 
 	unsignedTx, err := genUnSignTx(account, nonce, payload)
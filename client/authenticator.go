@@ -0,0 +1,183 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"strings"
+
+	"github.com/threeandtwo/aptclient/bcs"
+	"github.com/threeandtwo/aptclient/hexutil"
+	"github.com/threeandtwo/aptclient/key_manager"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// MultiKeyAccount is an AIP-55 account whose MultiKey mixes ed25519 and secp256k1 keys,
+// any MultiKey.SignaturesRequired of the keys sign for it
+type MultiKeyAccount struct {
+	Address  string
+	AuthKey  string
+	MultiKey *types.MultiKey
+
+	// signers by the index of their public key in MultiKey.PublicKeys
+	signers map[uint8]*types.AptAccount
+}
+
+// NewMultiKeyAccount returns the account of publicKeys, signers are the accounts of the keys held locally,
+// it only needs signers to sign transactions, not to derive the address
+func NewMultiKeyAccount(publicKeys []*types.AnyPublicKey, signaturesRequired uint8, signers ...*types.AptAccount) (*MultiKeyAccount, error) {
+	if len(publicKeys) == 0 || len(publicKeys) > types.MaxMultiKeyPublicKeys {
+		return nil, types.ErrMultiKeyCount
+	}
+
+	if signaturesRequired == 0 || int(signaturesRequired) > len(publicKeys) {
+		return nil, types.ErrMultiKeyThreshold
+	}
+
+	multiKey := &types.MultiKey{PublicKeys: publicKeys, SignaturesRequired: signaturesRequired}
+	authKey, err := MultiKeyAuthKey(multiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &MultiKeyAccount{
+		Address:  authKey,
+		AuthKey:  authKey,
+		MultiKey: multiKey,
+		signers:  make(map[uint8]*types.AptAccount),
+	}
+
+	for _, signer := range signers {
+		index, ok := account.indexOf(AnyPublicKey(signer))
+		if !ok {
+			return nil, types.ErrMultiKeyIndex
+		}
+		account.signers[index] = signer
+	}
+	return account, nil
+}
+
+func (m *MultiKeyAccount) indexOf(publicKey *types.AnyPublicKey) (uint8, bool) {
+	for i, k := range m.MultiKey.PublicKeys {
+		if k.Type == publicKey.Type && strings.EqualFold(k.Value, publicKey.Value) {
+			return uint8(i), true
+		}
+	}
+	return 0, false
+}
+
+// Sign signs msg by the signers of the lowest key indexes until SignaturesRequired is reached
+func (m *MultiKeyAccount) Sign(msg []byte) (*types.TxSignature, error) {
+	sender := &types.AccountSignature{
+		Type:               types.MultiKeySignature,
+		PublicKeys:         m.MultiKey.PublicKeys,
+		SignaturesRequired: m.MultiKey.SignaturesRequired,
+	}
+
+	for i := range m.MultiKey.PublicKeys {
+		if len(sender.Signatures) == int(m.MultiKey.SignaturesRequired) {
+			break
+		}
+
+		signer, ok := m.signers[uint8(i)]
+		if !ok {
+			continue
+		}
+
+		sig, err := anySign(signer, msg)
+		if err != nil {
+			return nil, err
+		}
+		sender.Signatures = append(sender.Signatures, &types.IndexedSignature{Index: uint8(i), Signature: sig})
+	}
+
+	if len(sender.Signatures) < int(m.MultiKey.SignaturesRequired) {
+		return nil, types.ErrMultiKeySigners
+	}
+
+	return &types.TxSignature{
+		Type:   types.SingleSender,
+		Sender: sender,
+	}, nil
+}
+
+func (a *AptClient) SignMultiKeyTransaction(account *MultiKeyAccount, unsignedTx *types.UnsignedTx) (*types.SignedTx, error) {
+	msg, err := a.SignMessage(unsignedTx)
+	if err != nil {
+		return nil, err
+	}
+
+	hexMsg, err := hex.DecodeString(msg.Message[2:])
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := account.Sign(hexMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SignedTx{
+		UnsignedTx: unsignedTx,
+		Signature:  sig,
+	}, nil
+}
+
+// AnyPublicKey returns the public key of account as used by the SingleKey and MultiKey schemes
+func AnyPublicKey(account *types.AptAccount) *types.AnyPublicKey {
+	if account.Curve == types.Secp256k1Curve {
+		return &types.AnyPublicKey{Type: types.Secp256k1Ecdsa, Value: account.PublicKey}
+	}
+	return &types.AnyPublicKey{Type: types.AnyEd25519, Value: account.PublicKey}
+}
+
+// SingleKeyAuthKey returns sha3-256(bcs(AnyPublicKey) || 0x02), the address of a fresh single key account
+func SingleKeyAuthKey(publicKey *types.AnyPublicKey) (string, error) {
+	b, err := bcs.Serialize(publicKey)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(key_manager.AuthKey(key_manager.SingleKeyScheme, b)), nil
+}
+
+// MultiKeyAuthKey returns sha3-256(bcs(MultiKey) || 0x03), the address of a fresh multi key account
+func MultiKeyAuthKey(multiKey *types.MultiKey) (string, error) {
+	b, err := bcs.Serialize(multiKey)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(key_manager.AuthKey(key_manager.MultiKeyScheme, b)), nil
+}
+
+// anySign signs msg with the key of account, secp256k1 keys sign sha3-256(msg)
+func anySign(account *types.AptAccount, msg []byte) (*types.AnySignature, error) {
+	if account.Curve == types.Secp256k1Curve {
+		if account.Secp256k1Key == nil {
+			return nil, types.ErrSecp256k1KeyNull
+		}
+
+		sig, err := key_manager.SignSecp256k1(account.Secp256k1Key, msg)
+		if err != nil {
+			return nil, err
+		}
+		return &types.AnySignature{Type: types.Secp256k1Ecdsa, Value: hexutil.Encode(sig)}, nil
+	}
+
+	return &types.AnySignature{Type: types.AnyEd25519, Value: hexutil.Encode(ed25519.Sign(account.PrivateKey, msg))}, nil
+}
+
+// singleKeySignature signs the signing message as a single_sender authenticator with a single key
+func singleKeySignature(account *types.AptAccount, msg []byte) (*types.TxSignature, error) {
+	sig, err := anySign(account, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.TxSignature{
+		Type: types.SingleSender,
+		Sender: &types.AccountSignature{
+			Type:      types.SingleKeySignature,
+			PublicKey: AnyPublicKey(account),
+			Signature: sig,
+		},
+	}, nil
+}
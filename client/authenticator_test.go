@@ -0,0 +1,151 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/threeandtwo/aptclient/bcs"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+func TestNewMultiKeyAccount(t *testing.T) {
+	edAccount, err := NewAptAccount(mnemonic, "").AccountFromMnemonic(0)
+	if err != nil {
+		t.Fatalf("ed25519 account error: %s", err)
+	}
+
+	secpAccount, err := NewSecp256k1Account(mnemonic, "").AccountFromMnemonic(0)
+	if err != nil {
+		t.Fatalf("secp256k1 account error: %s", err)
+	}
+
+	publicKeys := []*types.AnyPublicKey{AnyPublicKey(edAccount), AnyPublicKey(secpAccount)}
+
+	tests := []struct {
+		name               string
+		signaturesRequired uint8
+		signers            []*types.AptAccount
+		wantErr            error
+	}{
+		{
+			name:               "1 of 2 by secp256k1",
+			signaturesRequired: 1,
+			signers:            []*types.AptAccount{secpAccount},
+		},
+		{
+			name:               "2 of 2",
+			signaturesRequired: 2,
+			signers:            []*types.AptAccount{secpAccount, edAccount},
+		},
+		{
+			name:               "2 of 2 with 1 signer",
+			signaturesRequired: 2,
+			signers:            []*types.AptAccount{edAccount},
+			wantErr:            types.ErrMultiKeySigners,
+		},
+		{
+			name:               "threshold > keys",
+			signaturesRequired: 3,
+			wantErr:            types.ErrMultiKeyThreshold,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account, err := NewMultiKeyAccount(publicKeys, tt.signaturesRequired, tt.signers...)
+			if err == nil {
+				var sig *types.TxSignature
+				sig, err = account.Sign([]byte("This is a sample message"))
+				if err == nil {
+					if len(sig.Sender.Signatures) != int(tt.signaturesRequired) {
+						t.Errorf("%d signatures for %d required", len(sig.Sender.Signatures), tt.signaturesRequired)
+					}
+
+					if _, err := bcs.Serialize(sig); err != nil {
+						t.Errorf("bcs serialize error: %s", err)
+					}
+
+					b, _ := json.Marshal(sig.ForSimulation())
+					t.Logf("address: %s, simulation signature: %s", account.Address, string(b))
+				}
+			}
+
+			if err != tt.wantErr {
+				t.Errorf("error %v mismatched with %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMultiKeyAuthKey(t *testing.T) {
+	// sha3-256(02 | 00 20 <ed25519 key> | 01 41 <secp256k1 key> | 01 | 03)
+	multiKey := &types.MultiKey{
+		PublicKeys: []*types.AnyPublicKey{
+			{Type: types.AnyEd25519, Value: "0xd75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"},
+			{Type: types.Secp256k1Ecdsa, Value: "0x04acdd16651b839c24665b7e2033b55225f384554949fef46c397b5275f37f6ee95554d70fb5d9f93c5831ebf695c7206e7477ce708f03ae9bb2862dc6c9e033ea"},
+		},
+		SignaturesRequired: 1,
+	}
+	const want = "0xb4e6b518d38c94f64b35161e34f7c1bc8cba883cf6bb68c5e8550812a2bce2ee"
+
+	authKey, err := MultiKeyAuthKey(multiKey)
+	if err != nil || authKey != want {
+		t.Errorf("MultiKeyAuthKey() = %s, %v, want %s", authKey, err, want)
+	}
+
+	account, err := NewMultiKeyAccount(multiKey.PublicKeys, multiKey.SignaturesRequired)
+	if err != nil || account.Address != want {
+		t.Errorf("NewMultiKeyAccount() address = %v, %v, want %s", account, err, want)
+	}
+}
+
+func TestSingleKeyAuthKey(t *testing.T) {
+	account, err := NewSecp256k1Account("0xd107155adf816a0a94c6db3c9489c13ad8a1eda7ada2e558ba3bfa47c020347e", "").AccountFromPrivateKey()
+	if err != nil {
+		t.Fatalf("secp256k1 account error: %s", err)
+	}
+
+	authKey, err := SingleKeyAuthKey(AnyPublicKey(account))
+	if err != nil {
+		t.Fatalf("single key auth key error: %s", err)
+	}
+
+	if authKey != account.AuthKey {
+		t.Errorf("single key auth key %s mismatched with %s", authKey, account.AuthKey)
+	}
+}
+
+func TestSimulateTxSignature(t *testing.T) {
+	var posted struct {
+		Signature *types.TxSignature `json:"signature"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+			t.Errorf("decode body error: %s", err)
+		}
+		fmt.Fprint(w, `[{"success": true}]`)
+	}))
+	defer server.Close()
+
+	c, err := NewAptClient(server.URL)
+	if err != nil {
+		t.Fatalf("NewAptClient() error = %v", err)
+	}
+
+	signedTx := &types.SignedTx{
+		UnsignedTx: &types.UnsignedTx{Sender: "0x5", Payload: &types.ScriptPayload{Type: types.ScriptPayloadTy}},
+		Signature:  &types.TxSignature{Type: types.Ed25519, PublicKey: "0xaa", Signature: "0xbbcc"},
+	}
+	if _, err = c.SimulateTx(signedTx); err != nil {
+		t.Fatalf("SimulateTx() error = %v", err)
+	}
+	if posted.Signature == nil || posted.Signature.Signature != "0x0000" || posted.Signature.PublicKey != "0xaa" {
+		t.Errorf("simulated signature = %+v, want zeroed", posted.Signature)
+	}
+	if signedTx.Signature.Signature != "0xbbcc" {
+		t.Errorf("SimulateTx() changed the signature of signedTx to %s", signedTx.Signature.Signature)
+	}
+}
@@ -0,0 +1,382 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// events AnalyzeBalanceChanges derives balance changes from, by the sign of their amount
+var balanceEvents = map[string]int{
+	"0x1::coin::WithdrawEvent":      -1,
+	"0x1::coin::DepositEvent":       1,
+	"0x1::coin::CoinWithdraw":       -1,
+	"0x1::coin::CoinDeposit":        1,
+	"0x1::fungible_asset::Withdraw": -1,
+	"0x1::fungible_asset::Deposit":  1,
+}
+
+// BalanceChange is how much of Asset Address gained or lost in a transaction. Asset is the coin type of
+// coins and the metadata address of fungible assets, APT is types.AptCoinTy in both forms. Store is the
+// fungible store of fungible asset changes and empty for CoinStore changes. Before and After are the
+// balances of the store around the transaction, nil when unknown
+type BalanceChange struct {
+	Address string
+	Asset   string
+	Store   string
+	Delta   *big.Int
+	Before  *big.Int
+	After   *big.Int
+}
+
+// Reconciled reports whether After - Before is Delta, false when either balance is unknown
+func (c *BalanceChange) Reconciled() bool {
+	if c.Before == nil || c.After == nil {
+		return false
+	}
+	return new(big.Int).Sub(c.After, c.Before).Cmp(c.Delta) == 0
+}
+
+// StoreResolver returns the owner and metadata of a fungible store the write set does not hold
+type StoreResolver func(store string) (owner, metadata string, err error)
+
+// writeSet indexes the CoinStore and FungibleStore resources a transaction wrote
+type writeSet struct {
+	// coins maps address/coinType to the coin value, handles maps address/creationNum of
+	// CoinStore event handles to their coin type
+	coins   map[string]*big.Int
+	handles map[string]string
+	stores  map[string]*fungibleStoreState
+}
+
+type fungibleStoreState struct {
+	owner    string
+	metadata string
+	balance  *big.Int
+}
+
+// AnalyzeBalanceChanges derives the balance changes of tx from its coin and fungible asset withdraw and
+// deposit events, plus the gas fee gas_used * gas_unit_price minus the storage refund charged in APT to
+// the fee payer. The owners of fungible stores and the After balances come from the write set, stores it
+// does not hold are looked up by resolve. Changes netting to 0 are left out
+func AnalyzeBalanceChanges(tx *types.Transaction, resolve StoreResolver) ([]*BalanceChange, error) {
+	typed, err := tx.Typed()
+	if err != nil {
+		return nil, err
+	}
+
+	info, ok := typed.Info()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", types.ErrTxPending, typed.Type)
+	}
+
+	ws := indexWriteSet(info.Changes)
+	changes := make(map[string]*BalanceChange)
+	add := func(address, asset, store string, delta *big.Int) {
+		key := address + "/" + asset + "/" + store
+		c, ok := changes[key]
+		if !ok {
+			c = &BalanceChange{Address: address, Asset: asset, Store: store, Delta: new(big.Int)}
+			changes[key] = c
+		}
+		c.Delta.Add(c.Delta, delta)
+	}
+
+	events := typed.Events()
+	for i := range events {
+		e := &events[i]
+		sign, ok := balanceEvents[normalizeType(e.Type)]
+		if !ok {
+			continue
+		}
+
+		var data struct {
+			CoinType string    `json:"coin_type"`
+			Account  string    `json:"account"`
+			Store    string    `json:"store"`
+			Amount   types.U64 `json:"amount"`
+		}
+		if err = e.Decode(&data); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", types.ErrEventData, e.Type, err)
+		}
+		amount := new(big.Int).SetUint64(uint64(data.Amount))
+		if sign < 0 {
+			amount.Neg(amount)
+		}
+
+		switch {
+		case data.CoinType != "":
+			add(normalizeAddress(data.Account), normalizeType(data.CoinType), "", amount)
+		case data.Store != "":
+			store := normalizeAddress(data.Store)
+			state, err := ws.store(store, resolve)
+			if err != nil {
+				return nil, err
+			}
+			add(state.owner, faAsset(state.metadata), store, amount)
+		default:
+			// v1 events of the CoinStore event handles
+			owner := normalizeAddress(e.Guid.AccountAddress)
+			coinType, ok := ws.handles[owner+"/"+strconv.FormatUint(uint64(e.Guid.CreationNumber), 10)]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s of %s", types.ErrEventStore, e.Type, owner)
+			}
+			add(owner, coinType, "", amount)
+		}
+	}
+
+	if user, ok := typed.User(); ok {
+		payer, store, fee, err := gasFee(user, events, ws)
+		if err != nil {
+			return nil, err
+		}
+		add(payer, types.AptCoinTy, store, fee.Neg(fee))
+	}
+
+	list := make([]*BalanceChange, 0, len(changes))
+	for _, c := range changes {
+		if c.Delta.Sign() == 0 {
+			continue
+		}
+
+		if c.Store == "" {
+			c.After = ws.coins[c.Address+"/"+c.Asset]
+		} else if state, ok := ws.stores[c.Store]; ok {
+			c.After = state.balance
+		}
+		list = append(list, c)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Address != list[j].Address {
+			return list[i].Address < list[j].Address
+		}
+		if list[i].Asset != list[j].Asset {
+			return list[i].Asset < list[j].Asset
+		}
+		return list[i].Store < list[j].Store
+	})
+	return list, nil
+}
+
+// gasFee returns the APT fee the payer of user paid and the store it is paid from, empty for the CoinStore
+func gasFee(user *types.UserTransaction, events []types.TxEvents, ws *writeSet) (string, string, *big.Int, error) {
+	payer := normalizeAddress(user.Sender)
+	if user.Signature != nil {
+		if sig, ok := user.Signature.MultiAgent(); ok && sig.FeePayerAddress != "" {
+			payer = normalizeAddress(sig.FeePayerAddress)
+		}
+	}
+
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(uint64(user.GasUsed)), new(big.Int).SetUint64(uint64(user.GasUnitPrice)))
+	for i := range events {
+		if normalizeType(events[i].Type) != "0x1::transaction_fee::FeeStatement" {
+			continue
+		}
+
+		var statement types.FeeStatement
+		if err := events[i].Decode(&statement); err != nil {
+			return "", "", nil, fmt.Errorf("%w: %s: %v", types.ErrEventData, events[i].Type, err)
+		}
+		fee.Sub(fee, new(big.Int).SetUint64(uint64(statement.StorageFeeRefundOctas)))
+	}
+
+	if _, ok := ws.coins[payer+"/"+types.AptCoinTy]; ok {
+		return payer, "", fee, nil
+	}
+
+	store, err := PrimaryStoreAddress(payer, types.AptMetadataAddress)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return payer, normalizeAddress(store), fee, nil
+}
+
+func indexWriteSet(changes []*types.WriteSetChange) *writeSet {
+	ws := &writeSet{coins: make(map[string]*big.Int), handles: make(map[string]string), stores: make(map[string]*fungibleStoreState)}
+	store := func(address string) *fungibleStoreState {
+		if ws.stores[address] == nil {
+			ws.stores[address] = &fungibleStoreState{}
+		}
+		return ws.stores[address]
+	}
+
+	for _, change := range changes {
+		res, ok := change.WriteResource()
+		if !ok {
+			continue
+		}
+		address := normalizeAddress(change.Address)
+
+		if coinType, ok := coinStoreCoinType(normalizeType(res.Type)); ok {
+			var coinStore types.CoinStore
+			if decodeResource(res, &coinStore) != nil {
+				continue
+			}
+			if value, err := parseU64(coinStore.Coin.Value); err == nil {
+				ws.coins[address+"/"+coinType] = value
+			}
+			for _, handle := range []types.EventHandle{coinStore.DepositEvents, coinStore.WithdrawEvents} {
+				ws.handles[normalizeAddress(handle.Guid.Id.Addr)+"/"+handle.Guid.Id.CreationNum] = coinType
+			}
+			continue
+		}
+
+		switch normalizeType(res.Type) {
+		case types.FungibleStoreTy:
+			var fungibleStore types.FungibleStore
+			if decodeResource(res, &fungibleStore) != nil {
+				continue
+			}
+			state := store(address)
+			state.metadata = normalizeAddress(fungibleStore.Metadata.Inner)
+			if balance, err := parseU64(fungibleStore.Balance); err == nil && state.balance == nil {
+				state.balance = balance
+			}
+		case types.ConcurrentFungibleBalanceTy:
+			var concurrent types.ConcurrentFungibleBalance
+			if decodeResource(res, &concurrent) != nil {
+				continue
+			}
+			if balance, err := parseU64(concurrent.Balance.Value); err == nil {
+				store(address).balance = balance
+			}
+		case types.ObjectCoreTy:
+			var object types.ObjectCore
+			if decodeResource(res, &object) == nil {
+				store(address).owner = normalizeAddress(object.Owner)
+			}
+		}
+	}
+	return ws
+}
+
+// store returns the owner and metadata of a fungible store, by resolve when the write set lacks them
+func (ws *writeSet) store(address string, resolve StoreResolver) (*fungibleStoreState, error) {
+	state := ws.stores[address]
+	if state != nil && state.owner != "" && state.metadata != "" {
+		return state, nil
+	}
+
+	if resolve == nil {
+		return nil, fmt.Errorf("%w: %s", types.ErrEventStore, address)
+	}
+
+	owner, metadata, err := resolve(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if state == nil {
+		state = &fungibleStoreState{}
+		ws.stores[address] = state
+	}
+	if state.owner == "" {
+		state.owner = normalizeAddress(owner)
+	}
+	if state.metadata == "" {
+		state.metadata = normalizeAddress(metadata)
+	}
+	return state, nil
+}
+
+// faAsset returns the asset of metadata, APT is named by its coin type
+func faAsset(metadata string) string {
+	if metadata == normalizeAddress(types.AptMetadataAddress) {
+		return types.AptCoinTy
+	}
+	return metadata
+}
+
+// normalizeAddress prints address in its AIP-40 form, invalid addresses are kept as is
+func normalizeAddress(address string) string {
+	a, err := types.ParseAccountAddress(address)
+	if err != nil {
+		return address
+	}
+	return a.String()
+}
+
+// normalizeType prints the addresses of a Move type in their AIP-40 form
+func normalizeType(moveType string) string {
+	tag, err := types.ParseTypeTag(moveType)
+	if err != nil {
+		return moveType
+	}
+	return tag.String()
+}
+
+// BalanceChanges is AnalyzeBalanceChanges with the stores missing from the write set read at the version
+// of tx, and Before read at the version before it so every change can be checked by Reconciled. Before
+// stays nil when that version is pruned from the node
+func (a *AptClient) BalanceChanges(ctx context.Context, tx *types.Transaction) ([]*BalanceChange, error) {
+	version, err := strconv.ParseUint(tx.Version, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: version %q", types.ErrTxPending, tx.Version)
+	}
+
+	resolve := func(store string) (string, string, error) {
+		res, err := a.accountResource(ctx, store, types.ObjectCoreTy, tx.Version)
+		if err != nil {
+			return "", "", err
+		}
+		var object types.ObjectCore
+		if err = decodeResource(res, &object); err != nil {
+			return "", "", err
+		}
+
+		if res, err = a.accountResource(ctx, store, types.FungibleStoreTy, tx.Version); err != nil {
+			return "", "", err
+		}
+		var fungibleStore types.FungibleStore
+		if err = decodeResource(res, &fungibleStore); err != nil {
+			return "", "", err
+		}
+		return object.Owner, fungibleStore.Metadata.Inner, nil
+	}
+
+	changes, err := AnalyzeBalanceChanges(tx, resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range changes {
+		if c.After == nil {
+			if c.After, err = a.balanceAt(ctx, c, tx.Version); err != nil {
+				return nil, err
+			}
+		}
+
+		if version > 0 {
+			if c.Before, err = a.balanceAt(ctx, c, strconv.FormatUint(version-1, 10)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return changes, nil
+}
+
+// balanceAt reads the balance of the store of c at version, 0 when the store does not exist yet
+// and nil when version is pruned
+func (a *AptClient) balanceAt(ctx context.Context, c *BalanceChange, version string) (*big.Int, error) {
+	var balance *big.Int
+	var err error
+	if c.Store != "" {
+		balance, err = a.storeBalance(ctx, c.Store, version)
+	} else {
+		balance, err = a.coinBalance(ctx, c.Address, c.Asset, version)
+		if errors.Is(err, types.ErrResourceNotFound) {
+			return big.NewInt(0), nil
+		}
+	}
+
+	if errors.Is(err, types.ErrVersionPruned) {
+		return nil, nil
+	}
+	return balance, err
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// testTransferTransaction is 0x5 sending 100 APT from its primary store to 0x6 and 50 of
+// 0xcafe::m::C from its CoinStore to 0x7, paying 12 gas units at 100 with a storage refund of 10
+const testTransferTransaction = `{
+  "type": "user_transaction",
+  "version": "100",
+  "hash": "0xabc",
+  "gas_used": "12",
+  "success": true,
+  "vm_status": "Executed successfully",
+  "changes": [
+    {"type": "write_resource", "address": "%[1]s", "state_key_hash": "0x1", "data": {"type": "0x1::fungible_asset::FungibleStore", "data": {"metadata": {"inner": "0xa"}, "balance": "8710", "frozen": false}}},
+    {"type": "write_resource", "address": "%[1]s", "state_key_hash": "0x2", "data": {"type": "0x1::object::ObjectCore", "data": {"owner": "0x5", "allow_ungated_transfer": false, "guid_creation_num": "1"}}},
+    {"type": "write_resource", "address": "%[2]s", "state_key_hash": "0x3", "data": {"type": "0x1::fungible_asset::FungibleStore", "data": {"metadata": {"inner": "0xa"}, "balance": "600", "frozen": false}}},
+    {"type": "write_resource", "address": "0x5", "state_key_hash": "0x4", "data": {"type": "0xcafe::m::CoinStore", "data": {}}},
+    {"type": "write_resource", "address": "0x5", "state_key_hash": "0x5", "data": {"type": "0x1::coin::CoinStore<0xcafe::m::C>", "data": {"coin": {"value": "0"}, "frozen": false, "deposit_events": {"counter": "1", "guid": {"id": {"addr": "0x5", "creation_num": "4"}}}, "withdraw_events": {"counter": "1", "guid": {"id": {"addr": "0x5", "creation_num": "5"}}}}}},
+    {"type": "write_resource", "address": "0x7", "state_key_hash": "0x6", "data": {"type": "0x1::coin::CoinStore<0x000000000000000000000000000000000000000000000000000000000000cafe::m::C>", "data": {"coin": {"value": "50"}, "frozen": false, "deposit_events": {"counter": "1", "guid": {"id": {"addr": "0x7", "creation_num": "2"}}}, "withdraw_events": {"counter": "0", "guid": {"id": {"addr": "0x7", "creation_num": "3"}}}}}}
+  ],
+  "sender": "0x5",
+  "sequence_number": "1",
+  "max_gas_amount": "2000",
+  "gas_unit_price": "100",
+  "expiration_timestamp_secs": "1700000000",
+  "payload": {"type": "entry_function_payload", "function": "0xcafe::m::send", "type_arguments": [], "arguments": []},
+  "signature": {"type": "ed25519_signature", "public_key": "0xaa", "signature": "0xbb"},
+  "events": [
+    {"guid": {"creation_number": "0", "account_address": "0x0"}, "sequence_number": "0", "type": "0x1::fungible_asset::Withdraw", "data": {"store": "%[1]s", "amount": "100"}},
+    {"guid": {"creation_number": "0", "account_address": "0x0"}, "sequence_number": "0", "type": "0x1::fungible_asset::Deposit", "data": {"store": "%[2]s", "amount": "100"}},
+    {"guid": {"creation_number": "0", "account_address": "0x0"}, "sequence_number": "0", "type": "0x1::coin::CoinWithdraw", "data": {"coin_type": "0xcafe::m::C", "account": "0x5", "amount": "50"}},
+    {"guid": {"creation_number": "2", "account_address": "0x7"}, "sequence_number": "0", "type": "0x1::coin::DepositEvent", "data": {"amount": "50"}},
+    {"guid": {"creation_number": "0", "account_address": "0x0"}, "sequence_number": "0", "type": "0x1::transaction_fee::FeeStatement", "data": {"total_charge_gas_units": "12", "execution_gas_units": "4", "io_gas_units": "4", "storage_fee_octas": "400", "storage_fee_refund_octas": "10"}}
+  ],
+  "timestamp": "1699999999000000"
+}`
+
+func TestAnalyzeBalanceChanges(t *testing.T) {
+	senderStore, _ := PrimaryStoreAddress("0x5", types.AptMetadataAddress)
+	recipientStore, _ := PrimaryStoreAddress("0x6", types.AptMetadataAddress)
+	senderStore, recipientStore = normalizeAddress(senderStore), normalizeAddress(recipientStore)
+
+	var tx types.Transaction
+	if err := json.Unmarshal([]byte(fmt.Sprintf(testTransferTransaction, senderStore, recipientStore)), &tx); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	// the recipient store exists already, its ObjectCore is not in the write set
+	resolve := func(store string) (string, string, error) {
+		if store != recipientStore {
+			return "", "", fmt.Errorf("unexpected store %s", store)
+		}
+		return "0x6", "0x000000000000000000000000000000000000000000000000000000000000000a", nil
+	}
+
+	changes, err := AnalyzeBalanceChanges(&tx, resolve)
+	if err != nil {
+		t.Fatalf("AnalyzeBalanceChanges() error = %v", err)
+	}
+
+	// assets are printed in the AIP-40 form, 0xcafe is long
+	coinType := normalizeType("0xcafe::m::C")
+	want := []struct {
+		address, asset, store string
+		delta, after          int64
+	}{
+		{"0x5", coinType, "", -50, 0},
+		{"0x5", types.AptCoinTy, senderStore, -1290, 8710},
+		{"0x6", types.AptCoinTy, recipientStore, 100, 600},
+		{"0x7", coinType, "", 50, 50},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("AnalyzeBalanceChanges() = %d changes, want %d", len(changes), len(want))
+	}
+	for i, w := range want {
+		c := changes[i]
+		if c.Address != w.address || c.Asset != w.asset || c.Store != w.store || c.Delta.Int64() != w.delta || c.After == nil || c.After.Int64() != w.after {
+			t.Errorf("change %d = %+v, want %+v", i, c, w)
+		}
+	}
+
+	changes[1].Before = big.NewInt(10000)
+	if !changes[1].Reconciled() || changes[0].Reconciled() {
+		t.Errorf("Reconciled() = %v, %v, want true, false", changes[1].Reconciled(), changes[0].Reconciled())
+	}
+
+	if _, err = AnalyzeBalanceChanges(&tx, nil); !errors.Is(err, types.ErrEventStore) {
+		t.Errorf("AnalyzeBalanceChanges() without resolver error = %v, want %v", err, types.ErrEventStore)
+	}
+
+	if err = json.Unmarshal([]byte(`{"type": "pending_transaction", "hash": "0x1", "sender": "0x5"}`), &tx); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if _, err = AnalyzeBalanceChanges(&tx, resolve); !errors.Is(err, types.ErrTxPending) {
+		t.Errorf("AnalyzeBalanceChanges() of a pending transaction error = %v, want %v", err, types.ErrTxPending)
+	}
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// CoinStoreType returns 0x1::coin::CoinStore<coinType>
+func CoinStoreType(coinType string) string {
+	return fmt.Sprintf("%s<%s>", types.CoinStoreTy, coinType)
+}
+
+// CoinBalance returns the value of the CoinStore<coinType> of address,
+// the error wraps types.ErrResourceNotFound when address has no such store
+func (a *AptClient) CoinBalance(ctx context.Context, address types.AccountAddress, coinType string) (*big.Int, error) {
+	return a.coinBalance(ctx, address.String(), coinType, "")
+}
+
+func (a *AptClient) coinBalance(ctx context.Context, address, coinType, version string) (*big.Int, error) {
+	if coinType == "" {
+		return nil, types.ErrCoinTypeNull
+	}
+
+	res, err := a.accountResource(ctx, address, CoinStoreType(coinType), version)
+	if err != nil {
+		return nil, err
+	}
+
+	var store types.CoinStore
+	if err = decodeResource(res, &store); err != nil {
+		return nil, err
+	}
+	return parseU64(store.Coin.Value)
+}
+
+// CoinBalances returns the balance of every CoinStore of address, resources are streamed page by page
+func (a *AptClient) CoinBalances(address types.AccountAddress) ([]*types.CoinBalance, error) {
+	var balances []*types.CoinBalance
+	err := a.WalkAccountResources(context.Background(), address, "", 0, func(resources []*types.AccountResource) error {
+		for _, res := range resources {
+			coinType, ok := coinStoreCoinType(res.Type)
+			if !ok {
+				continue
+			}
+
+			var store types.CoinStore
+			if err := decodeResource(res, &store); err != nil {
+				return err
+			}
+
+			value, err := parseU64(store.Coin.Value)
+			if err != nil {
+				return err
+			}
+			balances = append(balances, &types.CoinBalance{CoinType: coinType, Value: value, Frozen: store.Frozen})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
+
+// CoinInfo reads the CoinInfo<coinType> published at the address of coinType,
+// an aggregator supply is read from its table
+func (a *AptClient) CoinInfo(coinType string) (*types.CoinInfo, error) {
+	if coinType == "" {
+		return nil, types.ErrCoinTypeNull
+	}
+
+	res, err := a.accountResource(context.Background(), strings.Split(coinType, "::")[0], fmt.Sprintf("%s<%s>", types.CoinInfoTy, coinType), "")
+	if err != nil {
+		return nil, err
+	}
+
+	var data types.CoinInfoData
+	if err = decodeResource(res, &data); err != nil {
+		return nil, err
+	}
+
+	info := &types.CoinInfo{
+		CoinType: coinType,
+		Name:     data.Name,
+		Symbol:   data.Symbol,
+		Decimals: data.Decimals,
+	}
+
+	if len(data.Supply.Vec) == 0 {
+		return info, nil
+	}
+
+	supply := data.Supply.Vec[0]
+	if len(supply.Integer.Vec) > 0 {
+		info.Supply, err = parseU128(supply.Integer.Vec[0].Value)
+		return info, err
+	}
+
+	if len(supply.Aggregator.Vec) > 0 {
+		aggregator := supply.Aggregator.Vec[0]
+		info.Supply, err = a.aggregatorValue(aggregator.Handle, aggregator.Key)
+	}
+	return info, err
+}
+
+// aggregatorValue reads the u128 value of an aggregator, kept in the table handle by its key address
+func (a *AptClient) aggregatorValue(handle, key string) (*big.Int, error) {
+	value := new(big.Int)
+	if err := a.TableItem(context.Background(), handle, "address", "u128", key, "", value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// coinStoreCoinType returns T of 0x1::coin::CoinStore<T>
+func coinStoreCoinType(resourceType string) (string, bool) {
+	prefix := types.CoinStoreTy + "<"
+	if !strings.HasPrefix(resourceType, prefix) || !strings.HasSuffix(resourceType, ">") {
+		return "", false
+	}
+	return resourceType[len(prefix) : len(resourceType)-1], true
+}
+
+// decodeResource decodes the data of res into v
+func decodeResource(res *types.AccountResource, v interface{}) error {
+	if res == nil || res.Data == nil {
+		return types.ErrResourceTypeNull
+	}
+
+	b, err := json.Marshal(res.Data)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(b, v); err != nil {
+		return types.ErrParsedValue
+	}
+	return nil
+}
+
+func parseU64(v string) (*big.Int, error) {
+	u, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetUint64(u), nil
+}
+
+func parseU128(v string) (*big.Int, error) {
+	u, ok := new(big.Int).SetString(v, 10)
+	if !ok || u.Sign() < 0 || u.BitLen() > 128 {
+		return nil, types.ErrParsedValue
+	}
+	return u, nil
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+func TestDecodeCoinStore(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource *types.AccountResource
+		coinType string
+		want     string
+		frozen   bool
+		wantErr  bool
+	}{
+		{
+			name: "above int64",
+			resource: &types.AccountResource{
+				Type: CoinStoreType(types.AptCoinTy),
+				Data: map[string]interface{}{"coin": map[string]interface{}{"value": "18446744073709551615"}, "frozen": true},
+			},
+			coinType: types.AptCoinTy,
+			want:     "18446744073709551615",
+			frozen:   true,
+		},
+		{
+			name: "nested coin type",
+			resource: &types.AccountResource{
+				Type: CoinStoreType("0x1::lp::LP<0x1::aptos_coin::AptosCoin, 0xa::b::C>"),
+				Data: map[string]interface{}{"coin": map[string]interface{}{"value": "1"}, "frozen": false},
+			},
+			coinType: "0x1::lp::LP<0x1::aptos_coin::AptosCoin, 0xa::b::C>",
+			want:     "1",
+		},
+		{
+			name: "above u64",
+			resource: &types.AccountResource{
+				Type: CoinStoreType(types.AptCoinTy),
+				Data: map[string]interface{}{"coin": map[string]interface{}{"value": "18446744073709551616"}},
+			},
+			coinType: types.AptCoinTy,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coinType, ok := coinStoreCoinType(tt.resource.Type)
+			if !ok || coinType != tt.coinType {
+				t.Errorf("coinStoreCoinType() = %s, want %s", coinType, tt.coinType)
+			}
+
+			var store types.CoinStore
+			if err := decodeResource(tt.resource, &store); err != nil {
+				t.Errorf("decodeResource() error = %v", err)
+				return
+			}
+
+			value, err := parseU64(store.Coin.Value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseU64() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (value.String() != tt.want || store.Frozen != tt.frozen) {
+				t.Errorf("balance = %s frozen %v, want %s frozen %v", value, store.Frozen, tt.want, tt.frozen)
+			}
+		})
+	}
+}
+
+func TestAptClient_CoinBalances(t *testing.T) {
+	tests := []struct {
+		name    string
+		rpc     string
+		address string
+	}{
+		{
+			name:    "coin balances",
+			rpc:     MAINNET_RPC_ADDR,
+			address: "0x1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewAptClient(tt.rpc)
+			if err != nil {
+				t.Logf("new apt client error: %s", err)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			balance, err := c.CoinBalance(ctx, types.MustParseAccountAddress(tt.address), "0x1::not_a::Coin")
+			if err != nil {
+				t.Logf("coin balance error: %s, not found %v", err, errors.Is(err, types.ErrResourceNotFound))
+			} else {
+				t.Logf("coin balance %s", balance)
+			}
+
+			balances, err := c.CoinBalances(types.MustParseAccountAddress(tt.address))
+			if err != nil {
+				t.Logf("coin balances error: %s", err)
+				return
+			}
+			for _, b := range balances {
+				t.Logf("%s: %s frozen %v", b.CoinType, b.Value, b.Frozen)
+			}
+
+			info, err := c.CoinInfo(types.AptCoinTy)
+			if err != nil {
+				t.Logf("coin info error: %s", err)
+				return
+			}
+			t.Logf("coin info: %+v", info)
+		})
+	}
+}
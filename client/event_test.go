@@ -0,0 +1,61 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+type testSwapEvent struct {
+	AmountIn  types.U64 `json:"amount_in"`
+	AmountOut types.U64 `json:"amount_out"`
+}
+
+func TestEventRegistry(t *testing.T) {
+	types.RegisterEvent("0xcafe::pool::SwapEvent", &testSwapEvent{})
+
+	const events = `[
+	  {"guid": {"creation_number": "0", "account_address": "0x0"}, "sequence_number": "0", "type": "0x1::fungible_asset::Withdraw", "data": {"store": "0xa", "amount": "100"}},
+	  {"guid": {"creation_number": "2", "account_address": "0xb"}, "sequence_number": "5", "type": "0x1::coin::DepositEvent", "data": {"amount": "90"}},
+	  {"guid": {"creation_number": "0", "account_address": "0x0"}, "sequence_number": "0", "type": "0x000000000000000000000000000000000000000000000000000000000000cafe::pool::SwapEvent<0x1::aptos_coin::AptosCoin, u8>", "data": {"amount_in": "3", "amount_out": "4"}},
+	  {"guid": {"creation_number": "0", "account_address": "0x0"}, "sequence_number": "0", "type": "0x1::transaction_fee::FeeStatement", "data": {"total_charge_gas_units": "bad"}},
+	  {"guid": {"creation_number": "0", "account_address": "0x0"}, "sequence_number": "0", "type": "0xdead::unknown::Event", "data": {"x": 1}}
+	]`
+
+	var got []types.TxEvents
+	if err := json.Unmarshal([]byte(events), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if withdraw, ok := got[0].Decoded.(*types.FAEvent); !ok || withdraw.Amount != 100 || withdraw.Store.String() != "0xa" {
+		t.Errorf("Decoded = %#v, want *types.FAEvent", got[0].Decoded)
+	}
+	if deposit, ok := got[1].Decoded.(*types.CoinAmountEvent); !ok || deposit.Amount != 90 || got[1].Guid.CreationNumber != 2 {
+		t.Errorf("Decoded = %#v, want *types.CoinAmountEvent", got[1].Decoded)
+	}
+	if swap, ok := got[2].Decoded.(*testSwapEvent); !ok || swap.AmountIn != 3 || swap.AmountOut != 4 {
+		t.Errorf("Decoded = %#v, want *testSwapEvent", got[2].Decoded)
+	}
+	if got[3].Decoded != nil || got[4].Decoded != nil {
+		t.Errorf("Decoded = %#v, %#v, want nil", got[3].Decoded, got[4].Decoded)
+	}
+
+	var unknown map[string]int
+	if err := got[4].Decode(&unknown); err != nil || unknown["x"] != 1 {
+		t.Errorf("Decode() = %v, %v", unknown, err)
+	}
+
+	if _, ok, err := types.DefaultEventRegistry.Decode(got[3].Type, got[3].Data); !ok || !errors.Is(err, types.ErrEventData) {
+		t.Errorf("Decode() = %v, %v, want %v", ok, err, types.ErrEventData)
+	}
+
+	var event types.Event
+	if err := json.Unmarshal([]byte(`{"version": "9", "guid": {"creation_number": "3", "account_address": "0xb"}, "sequence_number": "1", "type": "0x1::coin::WithdrawEvent", "data": {"amount": "7"}}`), &event); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if withdraw, ok := event.Decoded.(*types.CoinAmountEvent); !ok || withdraw.Amount != 7 {
+		t.Errorf("Decoded = %#v, want *types.CoinAmountEvent", event.Decoded)
+	}
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/threeandtwo/aptclient/hexutil"
+	"github.com/threeandtwo/aptclient/key_manager"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// PrimaryStoreAddress returns sha3-256(owner || metadata || 0xFC), the address of
+// primary_fungible_store::primary_store(owner, metadata)
+func PrimaryStoreAddress(owner, metadata string) (string, error) {
+	ownerAddress, err := types.ParseAccountAddress(owner)
+	if err != nil {
+		return "", err
+	}
+
+	metadataAddress, err := types.ParseAccountAddress(metadata)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(key_manager.AuthKey(key_manager.UserDerivedScheme, ownerAddress.Bytes(), metadataAddress.Bytes())), nil
+}
+
+// FABalance returns the balance of the primary store of owner for metadata,
+// it is 0 when the store is not created yet
+func (a *AptClient) FABalance(ctx context.Context, owner, metadata types.AccountAddress) (*big.Int, error) {
+	store, err := PrimaryStoreAddress(owner.String(), metadata.String())
+	if err != nil {
+		return nil, err
+	}
+	return a.storeBalance(ctx, store, "")
+}
+
+// storeBalance reads FungibleStore.balance at version, or ConcurrentFungibleBalance when the store
+// balance is kept in an aggregator
+func (a *AptClient) storeBalance(ctx context.Context, store, version string) (*big.Int, error) {
+	res, err := a.accountResource(ctx, store, types.FungibleStoreTy, version)
+	if errors.Is(err, types.ErrResourceNotFound) {
+		return big.NewInt(0), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var fungibleStore types.FungibleStore
+	if err = decodeResource(res, &fungibleStore); err != nil {
+		return nil, err
+	}
+
+	balance, err := parseU64(fungibleStore.Balance)
+	if err != nil || balance.Sign() > 0 {
+		return balance, err
+	}
+
+	res, err = a.accountResource(ctx, store, types.ConcurrentFungibleBalanceTy, version)
+	if errors.Is(err, types.ErrResourceNotFound) {
+		return balance, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var concurrent types.ConcurrentFungibleBalance
+	if err = decodeResource(res, &concurrent); err != nil {
+		return nil, err
+	}
+	return parseU64(concurrent.Balance.Value)
+}
+
+// AssetBalance returns the CoinStore<coinType> value plus the primary store balance of
+// its paired metadata, either part is 0 when absent
+func (a *AptClient) AssetBalance(ctx context.Context, owner types.AccountAddress, coinType string) (*big.Int, error) {
+	balance, err := a.CoinBalance(ctx, owner, coinType)
+	if errors.Is(err, types.ErrResourceNotFound) {
+		balance = big.NewInt(0)
+	} else if err != nil {
+		return nil, err
+	}
+
+	metadata, err := a.PairedMetadata(coinType)
+	if errors.Is(err, types.ErrTableItemNotFound) || errors.Is(err, types.ErrResourceNotFound) {
+		return balance, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	metadataAddress, err := types.ParseAccountAddress(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	faBalance, err := a.FABalance(ctx, owner, metadataAddress)
+	if err != nil {
+		return nil, err
+	}
+	return balance.Add(balance, faBalance), nil
+}
+
+// PairedMetadata returns the fungible asset metadata address paired with coinType in
+// CoinConversionMap, the error wraps types.ErrTableItemNotFound for unpaired coins
+func (a *AptClient) PairedMetadata(coinType string) (string, error) {
+	if coinType == "" {
+		return "", types.ErrCoinTypeNull
+	}
+	if coinType == types.AptCoinTy {
+		return types.AptMetadataAddress, nil
+	}
+
+	typeInfo, err := coinTypeInfo(coinType)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := a.AccountResourceByType(types.AccountOne, types.CoinConversionMapTy, "")
+	if err != nil {
+		return "", err
+	}
+
+	var conversion types.CoinConversionMap
+	if err = decodeResource(res, &conversion); err != nil {
+		return "", err
+	}
+
+	var metadata string
+	err = a.TableItem(context.Background(), conversion.CoinToFungibleAssetMap.Handle, "0x1::type_info::TypeInfo", "address", typeInfo, "", &metadata)
+	return metadata, err
+}
+
+// FAMetadata reads the 0x1::fungible_asset::Metadata of the metadata object
+func (a *AptClient) FAMetadata(metadata types.AccountAddress) (*types.FungibleAssetMetadata, error) {
+	res, err := a.AccountResourceByType(metadata, types.FungibleAssetMetadataTy, "")
+	if err != nil {
+		return nil, err
+	}
+
+	m := &types.FungibleAssetMetadata{}
+	if err = decodeResource(res, m); err != nil {
+		return nil, err
+	}
+	m.Address = metadata.String()
+	return m, nil
+}
+
+// FATransferPayload transfers amount of metadata from the primary store of the sender
+// to the primary store of recipient, creating it when needed. amount is rescaled to decimals,
+// the decimals of the metadata
+func FATransferPayload(metadata, recipient string, amount *types.Amount, decimals uint8) (*types.EntryFunctionPayload, error) {
+	value, err := amount.BaseUnits(decimals)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err = checkAccount(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	recipient, err = checkAccount(recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.EntryFunctionPayload{
+		Type:          types.EntryFunctionPayloadTy,
+		Function:      types.PrimaryFungibleStoreTransfer,
+		TypeArguments: []string{types.FungibleAssetMetadataTy},
+		Arguments:     []interface{}{metadata, recipient, strconv.FormatUint(value, 10)},
+	}, nil
+}
+
+// coinTypeInfo returns the JSON of the 0x1::type_info::TypeInfo of coinType,
+// the struct name keeps the type arguments of generic coins
+func coinTypeInfo(coinType string) (map[string]interface{}, error) {
+	parts := strings.SplitN(coinType, "::", 3)
+	if len(parts) != 3 {
+		return nil, types.ErrParsedValue
+	}
+
+	address, err := checkAccount(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"account_address": address,
+		"module_name":     hexutil.Encode([]byte(parts[1])),
+		"struct_name":     hexutil.Encode([]byte(parts[2])),
+	}, nil
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+func TestPrimaryStoreAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		owner    string
+		metadata string
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "apt primary store",
+			owner:    "0x5792c985bc96f436270bd2a3c692210b09c7febb8889345ceefdbae4bacfe498",
+			metadata: types.AptMetadataAddress,
+			want:     "0x02faf7de54e947595bf870f57f4ad1154800d059aa286ddcbb70c4cca53b91fb",
+		},
+		{
+			name:     "owner is null",
+			metadata: types.AptMetadataAddress,
+			wantErr:  types.ErrAddressNull,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PrimaryStoreAddress(tt.owner, tt.metadata)
+			if err != tt.wantErr {
+				t.Errorf("PrimaryStoreAddress() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("PrimaryStoreAddress() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFATransferPayload(t *testing.T) {
+	payload, err := FATransferPayload("0x000000000000000000000000000000000000000000000000000000000000000a", "0x5792c985bc96f436270bd2a3c692210b09c7febb8889345ceefdbae4bacfe498", types.NewAmount(big.NewInt(100), 8), 8)
+	if err != nil {
+		t.Fatalf("FATransferPayload() error = %v", err)
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	want := `{"type":"entry_function_payload","function":"0x1::primary_fungible_store::transfer","type_arguments":["0x1::fungible_asset::Metadata"],"arguments":["0xa","0x5792c985bc96f436270bd2a3c692210b09c7febb8889345ceefdbae4bacfe498","100"]}`
+	if string(b) != want {
+		t.Errorf("payload = %s, want %s", b, want)
+	}
+}
+
+func TestAptClient_AssetBalance(t *testing.T) {
+	tests := []struct {
+		name     string
+		rpc      string
+		address  string
+		coinType string
+	}{
+		{
+			name:     "apt coin and fa",
+			rpc:      MAINNET_RPC_ADDR,
+			address:  "0x1",
+			coinType: types.AptCoinTy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewAptClient(tt.rpc)
+			if err != nil {
+				t.Logf("new apt client error: %s", err)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			balance, err := c.AssetBalance(ctx, types.MustParseAccountAddress(tt.address), tt.coinType)
+			if err != nil {
+				t.Logf("asset balance error: %s", err)
+				return
+			}
+			t.Logf("asset balance %s of %s", balance, tt.address)
+
+			metadata, err := c.FAMetadata(types.MustParseAccountAddress(types.AptMetadataAddress))
+			if err != nil {
+				t.Logf("fa metadata error: %s", err)
+				return
+			}
+			t.Logf("fa metadata %+v", metadata)
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
+	"github.com/threeandtwo/aptclient/bcs"
+	"github.com/threeandtwo/aptclient/bytecode"
 	"github.com/threeandtwo/aptclient/types"
 	"math/big"
 )
@@ -14,36 +18,90 @@ type (
 
 		Sign(msg []byte) []byte
 		Verify(sig, msg []byte) bool
+		SignMessage(payload *types.SignMessagePayload) (*types.SignMessageResponse, error)
+	}
+
+	// IVerifier verifies signatures by a public key only, AuthKey ties the key to an account
+	IVerifier interface {
+		AuthKey() string
+		Verify(msg, sig []byte) bool
 	}
 
 	IClient interface {
 		NodeHealth(durationSecs uint32) (string, error)
 		LedgerInfo() (*types.LedgerInfo, error)
+		ChainId() (uint8, error)
 
 		BlockByHeight(blockHeight uint64, withTxs types.BlockWithTxs) (*types.Block, error)
 		BlockByVersion(version uint64, withTxs types.BlockWithTxs) (*types.Block, error)
 
-		Account(address string) (*types.Account, error)
-		GetBalance(address string) (*big.Int, error)
-		GetNonce(address string) (uint64, error)
-		AccountResources(address, version string) ([]*types.AccountResource, error)
-		AccountResourceByType(address, resourceType, version string) (*types.AccountResource, error)
-		AccountModules(address, version string) ([]*types.AccountModule, error)
-		AccountModuleById(address, moduleID, version string) (*types.AccountModule, error)
+		Account(address types.AccountAddress) (*types.Account, error)
+		GetBalance(address types.AccountAddress) (*big.Int, error)
+		GetNonce(address types.AccountAddress) (uint64, error)
+		CoinBalance(ctx context.Context, address types.AccountAddress, coinType string) (*big.Int, error)
+		CoinBalances(address types.AccountAddress) ([]*types.CoinBalance, error)
+		CoinInfo(coinType string) (*types.CoinInfo, error)
+		FABalance(ctx context.Context, owner, metadata types.AccountAddress) (*big.Int, error)
+		AssetBalance(ctx context.Context, owner types.AccountAddress, coinType string) (*big.Int, error)
+		PairedMetadata(coinType string) (string, error)
+		FAMetadata(metadata types.AccountAddress) (*types.FungibleAssetMetadata, error)
+		CanReceiveCoin(recipient types.AccountAddress, coinType string) (bool, error)
+
+		View(ctx context.Context, function string, typeArgs []string, args []interface{}, ledgerVersion string) ([]interface{}, error)
+		ViewInto(ctx context.Context, function string, typeArgs []string, args []interface{}, ledgerVersion string, out ...interface{}) error
+		ViewRaw(ctx context.Context, function string, typeArgs []string, args []interface{}, ledgerVersion string) ([]json.RawMessage, error)
+		ViewBCS(ctx context.Context, function string, typeArgs []string, args [][]byte, ledgerVersion string) ([]json.RawMessage, error)
+
+		TableItem(ctx context.Context, handle, keyType, valueType string, key interface{}, ledgerVersion string, out interface{}) error
+		RawTableItem(ctx context.Context, handle string, key []byte, ledgerVersion string) ([]byte, error)
+		RawTableItemInto(ctx context.Context, handle string, key []byte, ledgerVersion string, out bcs.Unmarshaler) error
+		AccountResources(address types.AccountAddress, version string) ([]*types.AccountResource, error)
+		AccountResourceByType(address types.AccountAddress, resourceType, version string) (*types.AccountResource, error)
+		AccountResourcesPage(ctx context.Context, address types.AccountAddress, version, cursor string, limit uint16) ([]*types.AccountResource, string, error)
+		WalkAccountResources(ctx context.Context, address types.AccountAddress, version string, limit uint16, fn func([]*types.AccountResource) error) error
+		AccountModules(address types.AccountAddress, version string) ([]*types.AccountModule, error)
+		AccountModulesPage(ctx context.Context, address types.AccountAddress, version, cursor string, limit uint16) ([]*types.AccountModule, string, error)
+		WalkAccountModules(ctx context.Context, address types.AccountAddress, version string, limit uint16, fn func([]*types.AccountModule) error) error
+		AccountModuleById(address types.AccountAddress, moduleID, version string) (*types.AccountModule, error)
+		ModuleABI(address types.AccountAddress, moduleName string) (*types.MoveModuleABI, error)
+		CompiledModule(address types.AccountAddress, moduleName, version string) (*bytecode.Module, error)
+		ValidatePayload(payload *types.EntryFunctionPayload) (*types.EntryFunctionPayload, error)
 
 		Transactions(limit uint16, start uint64) ([]*types.Transaction, error)
-		TransactionsByAccount(address string, limit uint16, start uint64) ([]*types.Transaction, error)
+		TransactionsByAccount(address types.AccountAddress, limit uint16, start uint64) ([]*types.Transaction, error)
+		IterTransactions(ctx context.Context, from uint64) *Iterator[*types.Transaction]
+		IterAccountTransactions(ctx context.Context, address types.AccountAddress, from uint64) *Iterator[*types.Transaction]
 		TransactionByHash(hash string) (*types.Transaction, error)
 		TransactionByVersion(version uint64) (*types.Transaction, error)
+		BalanceChanges(ctx context.Context, tx *types.Transaction) ([]*BalanceChange, error)
 		SignMessage(unSigTx *types.UnsignedTx) (*types.SigningMessage, error)
 		SignTransaction(account *types.AptAccount, unsignedTx *types.UnsignedTx) (*types.SignedTx, error)
+		SignMultiKeyTransaction(account *MultiKeyAccount, unsignedTx *types.UnsignedTx) (*types.SignedTx, error)
+		VerifySignatureByAddress(address types.AccountAddress, verifier IVerifier, msg, sig []byte) (bool, error)
+		VerifySignedMessageByAddress(address types.AccountAddress, publicKey *types.AnyPublicKey, resp *types.SignMessageResponse) (bool, error)
 		SubmitTx(signedTx *types.SignedTx) (*types.Transaction, error)
 		SimulateTx(signedTx *types.SignedTx) ([]*types.SimulateTx, error)
+		BuildTransaction(sender types.AccountAddress, payload interface{}, opts *TxOptions) (*types.UnsignedTx, error)
+		SignAndSubmit(account *types.AptAccount, unsignedTx *types.UnsignedTx) (*types.Transaction, error)
+		SubmitPayload(account *types.AptAccount, payload interface{}, opts *TxOptions) (*types.Transaction, error)
+		SubmitBCSTx(ctx context.Context, signedTx *types.SignedTransaction) (*types.Transaction, error)
+		SubmitPayloadBCS(ctx context.Context, account *types.AptAccount, payload bcs.Marshaler, opts *TxOptions) (*types.Transaction, error)
+		WaitForTransaction(ctx context.Context, hash string) (*types.Transaction, error)
+		PublishPackage(account *types.AptAccount, pkg *Package, opts *TxOptions) (*types.Transaction, error)
+		PublishPackageToObject(account *types.AptAccount, pkg *Package, opts *TxOptions) (*types.Transaction, string, error)
+		UpgradeObjectPackage(account *types.AptAccount, pkg *Package, codeObject types.AccountAddress, opts *TxOptions) (*types.Transaction, error)
+		StagingArea(ctx context.Context, owner types.AccountAddress, largePackages string) (*StagingArea, error)
+		PublishLargePackage(ctx context.Context, account *types.AptAccount, pkg *Package, opts *ChunkedPublishOptions) (*ChunkedPublishResult, error)
+		TransferAPT(account *types.AptAccount, recipient types.AccountAddress, amount *types.Amount, opts *TxOptions) (*types.Transaction, error)
+		TransferCoins(account *types.AptAccount, coinType string, recipient types.AccountAddress, amount *types.Amount, opts *TxOptions) (*types.Transaction, error)
+		BatchTransfer(account *types.AptAccount, coinType string, recipients []types.AccountAddress, amounts []*types.Amount, opts *TxOptions) (*types.Transaction, error)
 		SubmitBatchTx(signedTxs []*types.SignedTx) error
 		EstimateGasPrice() (uint64, error)
 
 		GetEventsByKey(key string, limit uint16, start uint64) ([]*types.Event, error)
-		GetEventsByCreationNumber(address, creationNumber string, limit, start uint64) ([]*types.Event, error)
-		GetEventsByHandle(address, handle, fieldName string, limit uint16, start uint64) ([]*types.Event, error)
+		GetEventsByCreationNumber(address types.AccountAddress, creationNumber string, limit, start uint64) ([]*types.Event, error)
+		GetEventsByHandle(address types.AccountAddress, handle, fieldName string, limit uint16, start uint64) ([]*types.Event, error)
+		IterEventsByCreationNumber(ctx context.Context, address types.AccountAddress, creationNumber string, from uint64) *Iterator[*types.Event]
+		IterEventsByHandle(ctx context.Context, address types.AccountAddress, handle, fieldName string, from uint64) *Iterator[*types.Event]
 	}
 )
@@ -0,0 +1,239 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// MaxPageSize is the most transactions or events the node returns per request
+const MaxPageSize = 100
+
+// Iterator pages through ledger transactions, account transactions or events by their version or
+// sequence number, so items committed while iterating are neither skipped nor repeated.
+//
+//	it := c.IterAccountTransactions(ctx, address, 0)
+//	for it.Next() {
+//		tx := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+//
+// Next returns false at the head of the ledger, a later Next polls again from there.
+// History pruned from the node is skipped.
+type Iterator[T any] struct {
+	ctx   context.Context
+	start uint64
+	limit uint16
+	page  []T
+	value T
+	err   error
+
+	fetch  func(ctx context.Context, start uint64, limit uint16) ([]T, error)
+	cursor func(T) (string, error)
+	// resume returns the first start not pruned, nil bisects it by fetch
+	resume func(ctx context.Context) (uint64, error)
+}
+
+func newIterator[T any](ctx context.Context, start uint64, fetch func(context.Context, uint64, uint16) ([]T, error), cursor func(T) (string, error)) *Iterator[T] {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &Iterator[T]{ctx: ctx, start: start, limit: MaxPageSize, fetch: fetch, cursor: cursor}
+}
+
+// IterTransactions iterates the ledger transactions from version from
+func (a *AptClient) IterTransactions(ctx context.Context, from uint64) *Iterator[*types.Transaction] {
+	rpc := fmt.Sprintf("%s/transactions", a.rpc)
+	it := newIterator(ctx, from, func(ctx context.Context, start uint64, limit uint16) ([]*types.Transaction, error) {
+		return a.transactions(ctx, rpc, limit, start)
+	}, func(tx *types.Transaction) (string, error) {
+		return tx.Version, nil
+	})
+	it.resume = func(ctx context.Context) (uint64, error) {
+		info, err := a.ledgerInfo(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return strconv.ParseUint(info.OldestLedgerVersion, 10, 64)
+	}
+	return it
+}
+
+// IterAccountTransactions iterates the transactions sent by address from sequence number from
+func (a *AptClient) IterAccountTransactions(ctx context.Context, address types.AccountAddress, from uint64) *Iterator[*types.Transaction] {
+	rpc := fmt.Sprintf("%s/accounts/%s/transactions", a.rpc, address)
+	return newIterator(ctx, from, func(ctx context.Context, start uint64, limit uint16) ([]*types.Transaction, error) {
+		return a.transactions(ctx, rpc, limit, start)
+	}, func(tx *types.Transaction) (string, error) {
+		return tx.SequenceNumber, nil
+	})
+}
+
+// IterEventsByCreationNumber iterates the events of the handle creationNumber of address from sequence number from
+func (a *AptClient) IterEventsByCreationNumber(ctx context.Context, address types.AccountAddress, creationNumber string, from uint64) *Iterator[*types.Event] {
+	rpc := fmt.Sprintf("%s/accounts/%s/events/%s", a.rpc, address, creationNumber)
+	return a.iterEvents(ctx, rpc, from)
+}
+
+// IterEventsByHandle iterates the events of the handle fieldName of the resource handle of address from sequence number from
+func (a *AptClient) IterEventsByHandle(ctx context.Context, address types.AccountAddress, handle, fieldName string, from uint64) *Iterator[*types.Event] {
+	if handle == "" || fieldName == "" {
+		return &Iterator[*types.Event]{err: fmt.Errorf("handle | fieldName is null, plz check it")}
+	}
+
+	rpc := fmt.Sprintf("%s/accounts/%s/events/%s/%s", a.rpc, address, handle, fieldName)
+	return a.iterEvents(ctx, rpc, from)
+}
+
+func (a *AptClient) iterEvents(ctx context.Context, rpc string, from uint64) *Iterator[*types.Event] {
+	return newIterator(ctx, from, func(ctx context.Context, start uint64, limit uint16) ([]*types.Event, error) {
+		return a.events(ctx, fmt.Sprintf("%s?limit=%d&start=%d", rpc, limit, start))
+	}, func(e *types.Event) (string, error) {
+		return e.SequenceNumber, nil
+	})
+}
+
+// WithPageSize sets the items fetched per request, capped at MaxPageSize
+func (it *Iterator[T]) WithPageSize(size uint16) *Iterator[T] {
+	if size > 0 && size <= MaxPageSize {
+		it.limit = size
+	}
+	return it
+}
+
+// Next advances to the next item, false when there is none or on error
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for len(it.page) == 0 {
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+
+		page, err := it.fetch(it.ctx, it.start, it.limit)
+		if errors.Is(err, types.ErrVersionAhead) {
+			// the ledger has not reached start yet
+			return false
+		}
+		if errors.Is(err, types.ErrVersionPruned) {
+			err = it.skipPruned()
+			if err == nil {
+				continue
+			}
+		}
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		if len(page) == 0 {
+			return false
+		}
+		it.page = page
+	}
+
+	it.value, it.page = it.page[0], it.page[1:]
+	cursor, err := it.cursor(it.value)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	pos, err := strconv.ParseUint(cursor, 10, 64)
+	if err != nil {
+		it.err = fmt.Errorf("%w: %q", types.ErrPageCursor, cursor)
+		return false
+	}
+	it.start = pos + 1
+	return true
+}
+
+// Value returns the item of the last successful Next
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the error that stopped the iterator
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Cursor returns the version or sequence number the next page starts from, an iterator created from it resumes here
+func (it *Iterator[T]) Cursor() uint64 {
+	return it.start
+}
+
+// skipPruned moves start past the pruned history
+func (it *Iterator[T]) skipPruned() error {
+	if it.resume != nil {
+		start, err := it.resume(it.ctx)
+		if err != nil {
+			return err
+		}
+		if start > it.start {
+			it.start = start
+			return nil
+		}
+	}
+
+	start, err := it.firstUnpruned()
+	if err != nil {
+		return err
+	}
+	it.start = start
+	return nil
+}
+
+// firstUnpruned returns the first position after start that is not pruned, nodes prune the
+// oldest history so it is found by doubling past start and bisecting back
+func (it *Iterator[T]) firstUnpruned() (uint64, error) {
+	lo, step := it.start, uint64(it.limit)
+	hi := lo + step
+	for {
+		pruned, err := it.pruned(hi)
+		if err != nil {
+			return 0, err
+		}
+		if !pruned {
+			break
+		}
+		lo, step = hi, step*2
+		hi = lo + step
+	}
+
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		pruned, err := it.pruned(mid)
+		if err != nil {
+			return 0, err
+		}
+		if pruned {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi, nil
+}
+
+func (it *Iterator[T]) pruned(start uint64) (bool, error) {
+	if err := it.ctx.Err(); err != nil {
+		return false, err
+	}
+
+	_, err := it.fetch(it.ctx, start, 1)
+	switch {
+	case errors.Is(err, types.ErrVersionPruned):
+		return true, nil
+	case errors.Is(err, types.ErrVersionAhead):
+		return false, nil
+	}
+	return false, err
+}
@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// testLedger serves versions [pruned, head) in pages capped at maxPage like a node,
+// starts at or past head fail like its invalid_input response
+type testLedger struct {
+	pruned, head uint64
+	maxPage      uint16
+	requests     int
+}
+
+func (l *testLedger) fetch(_ context.Context, start uint64, limit uint16) ([]*types.Transaction, error) {
+	l.requests++
+	if start < l.pruned {
+		return nil, fmt.Errorf("%w: version %d", types.ErrVersionPruned, start)
+	}
+	if start >= l.head {
+		return nil, fmt.Errorf("%w: Given start value (%d) is higher than the current ledger version, it must be < %d", types.ErrVersionAhead, start, l.head)
+	}
+	if limit > l.maxPage {
+		limit = l.maxPage
+	}
+
+	var txs []*types.Transaction
+	for v := start; v < l.head && v < start+uint64(limit); v++ {
+		txs = append(txs, &types.Transaction{Version: fmt.Sprintf("%d", v)})
+	}
+	return txs, nil
+}
+
+func (l *testLedger) iterator(from uint64) *Iterator[*types.Transaction] {
+	return newIterator(context.Background(), from, l.fetch, func(tx *types.Transaction) (string, error) {
+		return tx.Version, nil
+	})
+}
+
+func collect(it *Iterator[*types.Transaction], n int) []string {
+	var got []string
+	for len(got) < n && it.Next() {
+		got = append(got, it.Value().Version)
+	}
+	return got
+}
+
+func TestIterator(t *testing.T) {
+	tests := []struct {
+		name          string
+		ledger        testLedger
+		from          uint64
+		pageSize      uint16
+		want          string
+		wantRequests  int
+		wantNextStart uint64
+	}{
+		{"from genesis", testLedger{head: 5, maxPage: 100}, 0, 2, "[0 1 2 3 4]", 4, 5},
+		{"node caps page", testLedger{head: 5, maxPage: 2}, 1, 100, "[1 2 3 4]", 3, 5},
+		{"pruned", testLedger{pruned: 7, head: 9, maxPage: 100}, 0, 2, "[7 8]", 9, 9},
+		{"pruned far", testLedger{pruned: 1000, head: 1001, maxPage: 100}, 3, 100, "[1000]", 17, 1001},
+		{"past head", testLedger{head: 5, maxPage: 100}, 9, 0, "[]", 1, 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger := tt.ledger
+			it := ledger.iterator(tt.from).WithPageSize(tt.pageSize)
+			got := collect(it, 1<<10)
+			if fmt.Sprintf("%v", got) != tt.want || it.Err() != nil {
+				t.Errorf("Next() = %v, %v, want %s", got, it.Err(), tt.want)
+			}
+			if ledger.requests != tt.wantRequests || it.Cursor() != tt.wantNextStart {
+				t.Errorf("requests, Cursor() = %d, %d, want %d, %d", ledger.requests, it.Cursor(), tt.wantRequests, tt.wantNextStart)
+			}
+		})
+	}
+
+	t.Run("ledger growth", func(t *testing.T) {
+		ledger := testLedger{head: 3, maxPage: 100}
+		it := ledger.iterator(0).WithPageSize(2)
+		if got := collect(it, 1); fmt.Sprintf("%v", got) != "[0]" {
+			t.Fatalf("Next() = %v", got)
+		}
+
+		// versions committed while iterating follow without repeats, the head is polled again
+		ledger.head = 6
+		if got := collect(it, 1<<10); fmt.Sprintf("%v", got) != "[1 2 3 4 5]" {
+			t.Errorf("Next() = %v", got)
+		}
+		ledger.head = 7
+		if got := collect(it, 1<<10); fmt.Sprintf("%v", got) != "[6]" || it.Err() != nil {
+			t.Errorf("Next() after growth = %v, %v", got, it.Err())
+		}
+	})
+
+	t.Run("node past head", func(t *testing.T) {
+		head := 2
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+			if start >= head {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, `{"message": "Given start value (%d) is higher than the current ledger version, it must be < %d", "error_code": "invalid_input"}`, start, head)
+				return
+			}
+			fmt.Fprintf(w, `[{"type": "user_transaction", "version": "%d"}]`, start)
+		}))
+		defer server.Close()
+
+		c, err := NewAptClient(server.URL)
+		if err != nil {
+			t.Fatalf("NewAptClient() error = %v", err)
+		}
+		it := c.IterTransactions(context.Background(), 0)
+		if got := collect(it, 1<<10); fmt.Sprintf("%v", got) != "[0 1]" || it.Err() != nil {
+			t.Fatalf("Next() = %v, %v", got, it.Err())
+		}
+		head = 3
+		if got := collect(it, 1<<10); fmt.Sprintf("%v", got) != "[2]" || it.Err() != nil {
+			t.Errorf("Next() after growth = %v, %v", got, it.Err())
+		}
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		ledger := testLedger{pruned: 50, head: 52, maxPage: 100}
+		it := ledger.iterator(0)
+		it.resume = func(context.Context) (uint64, error) { return 50, nil }
+		if got := collect(it, 1<<10); fmt.Sprintf("%v", got) != "[50 51]" || ledger.requests != 3 {
+			t.Errorf("Next() = %v after %d requests", got, ledger.requests)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		failed := errors.New("failed")
+		it := newIterator(context.Background(), 0, func(context.Context, uint64, uint16) ([]*types.Transaction, error) {
+			return nil, failed
+		}, nil)
+		if it.Next() || !errors.Is(it.Err(), failed) {
+			t.Errorf("Next() error = %v, want %v", it.Err(), failed)
+		}
+
+		ledger := testLedger{head: 5, maxPage: 100}
+		it = newIterator(context.Background(), 0, ledger.fetch, func(*types.Transaction) (string, error) { return "", nil })
+		if it.Next() || !errors.Is(it.Err(), types.ErrPageCursor) {
+			t.Errorf("Next() error = %v, want %v", it.Err(), types.ErrPageCursor)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		it = newIterator(ctx, 0, ledger.fetch, nil)
+		if it.Next() || !errors.Is(it.Err(), context.Canceled) {
+			t.Errorf("Next() error = %v, want %v", it.Err(), context.Canceled)
+		}
+
+		c, _ := NewAptClient(RPC_ADDR)
+		if it := c.IterEventsByHandle(context.Background(), types.AccountOne, "", "", 0); it.Next() || it.Err() == nil {
+			t.Errorf("IterEventsByHandle() with a null handle error = %v", it.Err())
+		}
+	})
+}
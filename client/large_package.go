@@ -0,0 +1,409 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/threeandtwo/aptclient/hexutil"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+const (
+	// LargePackagesAddress is where large_packages is deployed on mainnet and testnet
+	LargePackagesAddress = "0x0e1ca3011bdd07246d4d16d909dbb2d6953a86c4735d5acf5865d962c630cce7"
+
+	// ChunkSize is the metadata and bytecode bytes `aptos move publish --chunked-publish` stages per transaction
+	ChunkSize = 55000
+
+	largePackagesModule = "large_packages"
+	stagingAreaStruct   = "StagingArea"
+	smartTableEntryTy   = "vector<0x1::smart_table::Entry<u64, vector<u8>>>"
+)
+
+// large_packages entry functions, the last chunk is staged by the one of the PublishTarget
+const (
+	StageCodeChunkFunc                 = "stage_code_chunk"
+	StageCodeChunkAndPublishToAccount  = "stage_code_chunk_and_publish_to_account"
+	StageCodeChunkAndPublishToObject   = "stage_code_chunk_and_publish_to_object"
+	StageCodeChunkAndUpgradeObjectCode = "stage_code_chunk_and_upgrade_object_code"
+	CleanupStagingAreaFunc             = "cleanup_staging_area"
+)
+
+// PublishTarget is where a chunked package is published
+type PublishTarget int
+
+const (
+	PublishToAccount PublishTarget = iota
+	PublishToObject
+	UpgradeObjectCode
+)
+
+// ChunkedPublishOptions configures PublishLargePackage, zero values keep the defaults
+type ChunkedPublishOptions struct {
+	// LargePackages is the address of large_packages, LargePackagesAddress when empty
+	LargePackages string
+	// ChunkSize is ChunkSize when 0
+	ChunkSize int
+	Target    PublishTarget
+	// CodeObject is the object UpgradeObjectCode upgrades
+	CodeObject string
+	// CleanupStale cleans a staging area holding another package up instead of failing with types.ErrStagingArea
+	CleanupStale bool
+	// Tx applies to every transaction, SequenceNumber is the one of the first
+	Tx *TxOptions
+}
+
+func (o *ChunkedPublishOptions) largePackages() string {
+	if o.LargePackages == "" {
+		return LargePackagesAddress
+	}
+	return o.LargePackages
+}
+
+// PackageChunk is the part of a package one large_packages transaction stages, Code[i] is appended
+// to the module CodeIndices[i]
+type PackageChunk struct {
+	Metadata    []byte
+	CodeIndices []uint16
+	Code        [][]byte
+}
+
+// ChunkPackage splits pkg like `aptos move publish --chunked-publish`: the metadata first, then the modules
+// in order, every chunk holding at most chunkSize bytes
+func ChunkPackage(pkg *Package, chunkSize int) ([]*PackageChunk, error) {
+	if pkg == nil || len(pkg.Modules) == 0 {
+		return nil, types.ErrPackageEmpty
+	}
+
+	if chunkSize <= 0 {
+		chunkSize = ChunkSize
+	}
+
+	metadata := splitBytes(pkg.Metadata, chunkSize)
+	chunks := make([]*PackageChunk, 0, len(metadata))
+	for _, m := range metadata[:len(metadata)-1] {
+		chunks = append(chunks, &PackageChunk{Metadata: m})
+	}
+
+	// the last metadata chunk shares its transaction with the first code chunks
+	chunk := &PackageChunk{Metadata: metadata[len(metadata)-1]}
+	taken := len(chunk.Metadata)
+	for i, module := range pkg.Modules {
+		for _, code := range splitBytes(module, chunkSize) {
+			if taken+len(code) > chunkSize {
+				chunks = append(chunks, chunk)
+				chunk, taken = &PackageChunk{}, 0
+			}
+			chunk.CodeIndices = append(chunk.CodeIndices, uint16(i))
+			chunk.Code = append(chunk.Code, code)
+			taken += len(code)
+		}
+	}
+	return append(chunks, chunk), nil
+}
+
+// splitBytes splits b into parts of at most size bytes, an empty b is one empty part
+func splitBytes(b []byte, size int) [][]byte {
+	parts := make([][]byte, 0, len(b)/size+1)
+	for len(b) > size {
+		parts = append(parts, b[:size])
+		b = b[size:]
+	}
+	return append(parts, b)
+}
+
+// ChunkedPublishPayloads returns the large_packages payloads publishing pkg in order, the last one
+// stages the last chunk and publishes to opts.Target
+func ChunkedPublishPayloads(pkg *Package, opts *ChunkedPublishOptions) ([]*types.EntryFunctionPayload, error) {
+	if opts == nil {
+		opts = &ChunkedPublishOptions{}
+	}
+
+	chunks, err := ChunkPackage(pkg, opts.ChunkSize)
+	if err != nil {
+		return nil, err
+	}
+
+	payloads := make([]*types.EntryFunctionPayload, 0, len(chunks))
+	for i, chunk := range chunks {
+		function := StageCodeChunkFunc
+		if i == len(chunks)-1 {
+			if function, err = opts.publishFunc(); err != nil {
+				return nil, err
+			}
+		}
+
+		payload, err := stageChunkPayload(opts.largePackages(), function, chunk)
+		if err != nil {
+			return nil, err
+		}
+
+		if function == StageCodeChunkAndUpgradeObjectCode {
+			codeObject, err := checkAccount(opts.CodeObject)
+			if err != nil {
+				return nil, err
+			}
+			payload.Arguments = append(payload.Arguments, codeObject)
+		}
+		payloads = append(payloads, payload)
+	}
+	return payloads, nil
+}
+
+func (o *ChunkedPublishOptions) publishFunc() (string, error) {
+	switch o.Target {
+	case PublishToAccount:
+		return StageCodeChunkAndPublishToAccount, nil
+	case PublishToObject:
+		return StageCodeChunkAndPublishToObject, nil
+	case UpgradeObjectCode:
+		return StageCodeChunkAndUpgradeObjectCode, nil
+	}
+	return "", fmt.Errorf("unknown publish target %d", o.Target)
+}
+
+func stageChunkPayload(largePackages, function string, chunk *PackageChunk) (*types.EntryFunctionPayload, error) {
+	address, err := checkAccount(largePackages)
+	if err != nil {
+		return nil, err
+	}
+
+	// vector<u16> is a JSON array of numbers
+	indices := make([]interface{}, 0, len(chunk.CodeIndices))
+	for _, i := range chunk.CodeIndices {
+		indices = append(indices, i)
+	}
+
+	code := make([]interface{}, 0, len(chunk.Code))
+	for _, c := range chunk.Code {
+		code = append(code, hexutil.Encode(c))
+	}
+
+	return &types.EntryFunctionPayload{
+		Type:          types.EntryFunctionPayloadTy,
+		Function:      address + "::" + largePackagesModule + "::" + function,
+		TypeArguments: []string{},
+		Arguments:     []interface{}{hexutil.Encode(chunk.Metadata), indices, code},
+	}, nil
+}
+
+// CleanupStagingAreaPayload drops the staging area of the sender
+func CleanupStagingAreaPayload(largePackages string) (*types.EntryFunctionPayload, error) {
+	if largePackages == "" {
+		largePackages = LargePackagesAddress
+	}
+
+	address, err := checkAccount(largePackages)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.EntryFunctionPayload{
+		Type:          types.EntryFunctionPayloadTy,
+		Function:      address + "::" + largePackagesModule + "::" + CleanupStagingAreaFunc,
+		TypeArguments: []string{},
+		Arguments:     []interface{}{},
+	}, nil
+}
+
+// StagingArea is the package an account has staged in large_packages, Code maps module indexes to
+// their staged bytecode
+type StagingArea struct {
+	Metadata      []byte
+	Code          map[uint16][]byte
+	LastModuleIdx uint64
+}
+
+// staged returns the staging area after the first n chunks
+func staged(chunks []*PackageChunk, n int) *StagingArea {
+	area := &StagingArea{Code: make(map[uint16][]byte)}
+	for _, chunk := range chunks[:n] {
+		area.Metadata = append(area.Metadata, chunk.Metadata...)
+		for i, idx := range chunk.CodeIndices {
+			area.Code[idx] = append(area.Code[idx], chunk.Code[i]...)
+		}
+	}
+	return area
+}
+
+func (s *StagingArea) equal(o *StagingArea) bool {
+	if !bytes.Equal(s.Metadata, o.Metadata) || len(s.Code) != len(o.Code) {
+		return false
+	}
+
+	for idx, code := range s.Code {
+		if !bytes.Equal(code, o.Code[idx]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ResumeChunk returns how many chunks area holds already, false when it holds another package
+func ResumeChunk(chunks []*PackageChunk, area *StagingArea) (int, bool) {
+	if area == nil {
+		return 0, true
+	}
+
+	// the last chunk publishes and drops the staging area, it is never staged
+	for n := len(chunks) - 1; n >= 0; n-- {
+		if staged(chunks, n).equal(area) {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// StagingArea reads the package owner has staged in large_packages, nil when it has none
+func (a *AptClient) StagingArea(ctx context.Context, owner types.AccountAddress, largePackages string) (*StagingArea, error) {
+	if largePackages == "" {
+		largePackages = LargePackagesAddress
+	}
+
+	address, err := checkAccount(largePackages)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := a.accountResource(ctx, owner.String(), address+"::"+largePackagesModule+"::"+stagingAreaStruct, "")
+	if errors.Is(err, types.ErrResourceNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		Metadata types.HexBytes `json:"metadata_serialized"`
+		Code     struct {
+			Buckets struct {
+				Inner struct {
+					Handle string `json:"handle"`
+				} `json:"inner"`
+			} `json:"buckets"`
+			NumBuckets types.U64 `json:"num_buckets"`
+		} `json:"code"`
+		LastModuleIdx types.U64 `json:"last_module_idx"`
+	}
+	if err = decodeResource(res, &data); err != nil {
+		return nil, err
+	}
+
+	area := &StagingArea{Metadata: data.Metadata, Code: make(map[uint16][]byte), LastModuleIdx: uint64(data.LastModuleIdx)}
+
+	// code is a SmartTable<u64, vector<u8>>, its buckets are the items 0 to num_buckets-1 of the inner table
+	for bucket := uint64(0); bucket < uint64(data.Code.NumBuckets); bucket++ {
+		var entries []struct {
+			Key   types.U64      `json:"key"`
+			Value types.HexBytes `json:"value"`
+		}
+		err = a.TableItem(ctx, data.Code.Buckets.Inner.Handle, "u64", smartTableEntryTy, bucket, "", &entries)
+		if errors.Is(err, types.ErrTableItemNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range entries {
+			area.Code[uint16(e.Key)] = e.Value
+		}
+	}
+	return area, nil
+}
+
+// ChunkedPublishResult is the outcome of PublishLargePackage, Resumed is the number of chunks
+// staged before the call and CodeObject the object a PublishToObject package is published to
+type ChunkedPublishResult struct {
+	Transactions []*types.Transaction
+	Resumed      int
+	CodeObject   string
+}
+
+// PublishLargePackage publishes pkg by the large_packages staging flow: it reads the staging area of account
+// to skip the chunks staged by an interrupted call, then submits the remaining chunks with consecutive
+// sequence numbers, waiting for each to commit so a failed or expired chunk stops the flow and can be resumed
+func (a *AptClient) PublishLargePackage(ctx context.Context, account *types.AptAccount, pkg *Package, opts *ChunkedPublishOptions) (*ChunkedPublishResult, error) {
+	if opts == nil {
+		opts = &ChunkedPublishOptions{}
+	}
+
+	chunks, err := ChunkPackage(pkg, opts.ChunkSize)
+	if err != nil {
+		return nil, err
+	}
+
+	payloads, err := ChunkedPublishPayloads(pkg, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sender, err := types.ParseAccountAddress(account.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	area, err := a.StagingArea(ctx, sender, opts.largePackages())
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ChunkedPublishResult{}
+	resumed, ok := ResumeChunk(chunks, area)
+	if !ok {
+		if !opts.CleanupStale {
+			return nil, types.ErrStagingArea
+		}
+
+		cleanup, err := CleanupStagingAreaPayload(opts.LargePackages)
+		if err != nil {
+			return nil, err
+		}
+		payloads = append([]*types.EntryFunctionPayload{cleanup}, payloads...)
+	}
+	result.Resumed = resumed
+
+	txOpts := TxOptions{}
+	if opts.Tx != nil {
+		txOpts = *opts.Tx
+	}
+	if txOpts.SequenceNumber == nil {
+		nonce, err := a.GetNonce(sender)
+		if err != nil {
+			return nil, err
+		}
+		txOpts.SequenceNumber = &nonce
+	}
+
+	for _, payload := range payloads[resumed:] {
+		unsignedTx, err := a.BuildTransaction(sender, payload, &txOpts)
+		if err != nil {
+			return result, err
+		}
+
+		if opts.Target == PublishToObject {
+			if result.CodeObject, err = ObjectCodeAddress(account.Address, unsignedTx.SequenceNumber); err != nil {
+				return result, err
+			}
+		}
+
+		pending, err := a.SignAndSubmit(account, unsignedTx)
+		if err != nil {
+			return result, err
+		}
+
+		tx, err := a.waitForTransaction(ctx, pending.Hash, unsignedTx.ExpirationTime)
+		if tx != nil {
+			result.Transactions = append(result.Transactions, tx)
+		}
+		if err != nil {
+			return result, err
+		}
+
+		nonce := *txOpts.SequenceNumber + 1
+		txOpts.SequenceNumber = &nonce
+	}
+	return result, nil
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestChunkPackage(t *testing.T) {
+	pkg := &Package{
+		Metadata: bytes.Repeat([]byte{0x01}, 25),
+		Modules:  [][]byte{bytes.Repeat([]byte{0x02}, 12), bytes.Repeat([]byte{0x03}, 3), bytes.Repeat([]byte{0x04}, 9)},
+	}
+
+	tests := []struct {
+		name      string
+		chunkSize int
+		target    PublishTarget
+		// want is the metadata bytes and module indexes of every chunk
+		want     []string
+		wantLast string
+	}{
+		{
+			name:      "single chunk",
+			chunkSize: 100,
+			want:      []string{"25 [0 1 2]"},
+			wantLast:  StageCodeChunkAndPublishToAccount,
+		},
+		{
+			name:      "metadata and modules split",
+			chunkSize: 10,
+			target:    PublishToObject,
+			want:      []string{"10 []", "10 []", "5 []", "0 [0]", "0 [0 1]", "0 [2]"},
+			wantLast:  StageCodeChunkAndPublishToObject,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks, err := ChunkPackage(pkg, tt.chunkSize)
+			if err != nil {
+				t.Fatalf("ChunkPackage() error = %v", err)
+			}
+
+			var got []string
+			for _, c := range chunks {
+				size := len(c.Metadata)
+				for _, code := range c.Code {
+					size += len(code)
+				}
+				if size > tt.chunkSize {
+					t.Errorf("ChunkPackage() chunk of %d bytes", size)
+				}
+				got = append(got, fmt.Sprintf("%d %v", len(c.Metadata), c.CodeIndices))
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("ChunkPackage() = %v, want %v", got, tt.want)
+			}
+
+			// staging every chunk rebuilds the package
+			area := staged(chunks, len(chunks))
+			if !bytes.Equal(area.Metadata, pkg.Metadata) {
+				t.Errorf("staged metadata = %x", area.Metadata)
+			}
+			for i, module := range pkg.Modules {
+				if !bytes.Equal(area.Code[uint16(i)], module) {
+					t.Errorf("staged module %d = %x", i, area.Code[uint16(i)])
+				}
+			}
+
+			payloads, err := ChunkedPublishPayloads(pkg, &ChunkedPublishOptions{ChunkSize: tt.chunkSize, Target: tt.target, LargePackages: "0x7"})
+			if err != nil {
+				t.Fatalf("ChunkedPublishPayloads() error = %v", err)
+			}
+			if len(payloads) != len(chunks) || payloads[len(payloads)-1].Function != "0x7::large_packages::"+tt.wantLast {
+				t.Errorf("ChunkedPublishPayloads() = %d payloads, last %s", len(payloads), payloads[len(payloads)-1].Function)
+			}
+			for _, p := range payloads[:len(payloads)-1] {
+				if p.Function != "0x7::large_packages::"+StageCodeChunkFunc || len(p.Arguments) != 3 {
+					t.Errorf("ChunkedPublishPayloads() payload = %+v", p)
+				}
+			}
+		})
+	}
+
+	if _, err := ChunkedPublishPayloads(pkg, &ChunkedPublishOptions{Target: UpgradeObjectCode}); err == nil {
+		t.Errorf("ChunkedPublishPayloads() upgrade without code object error = nil")
+	}
+}
+
+func TestResumeChunk(t *testing.T) {
+	pkg := &Package{Metadata: []byte{1, 2, 3}, Modules: [][]byte{{4, 5, 6, 7}, {8, 9}}}
+	chunks, err := ChunkPackage(pkg, 3)
+	if err != nil {
+		t.Fatalf("ChunkPackage() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		area   *StagingArea
+		want   int
+		wantOk bool
+	}{
+		{name: "nothing staged", area: nil, want: 0, wantOk: true},
+		{name: "metadata staged", area: staged(chunks, 1), want: 1, wantOk: true},
+		{name: "code staged", area: staged(chunks, 2), want: 2, wantOk: true},
+		{name: "another package", area: &StagingArea{Metadata: []byte{9}, Code: map[uint16][]byte{}}, wantOk: false},
+		{name: "other code", area: &StagingArea{Metadata: []byte{1, 2, 3}, Code: map[uint16][]byte{0: {4, 4, 4}}}, wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ResumeChunk(chunks, tt.area)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("ResumeChunk() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+
+	if payload, err := CleanupStagingAreaPayload(""); err != nil || payload.Function != LargePackagesAddress+"::large_packages::"+CleanupStagingAreaFunc {
+		t.Errorf("CleanupStagingAreaPayload() = %v, %v", payload, err)
+	}
+}
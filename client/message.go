@@ -0,0 +1,106 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"strings"
+
+	"github.com/threeandtwo/aptclient/hexutil"
+	"github.com/threeandtwo/aptclient/key_manager"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// signMessagePrefix separates off-chain messages from transactions, a signing message
+// of a transaction starts with sha3-256("APTOS::RawTransaction") so it can't collide
+const signMessagePrefix = "APTOS"
+
+// SignMessage signs an off-chain message the way wallets implement the Aptos wallet standard signMessage,
+// docs in https://github.com/aptos-labs/wallet-standard
+func (a *AptAccount) SignMessage(payload *types.SignMessagePayload) (*types.SignMessageResponse, error) {
+	if a.prvKey == nil && a.secpKey == nil {
+		return nil, types.ErrKeyNotLoaded
+	}
+
+	resp := &types.SignMessageResponse{
+		Application: payload.Application,
+		ChainId:     payload.ChainId,
+		Message:     payload.Message,
+		Nonce:       payload.Nonce,
+		Prefix:      signMessagePrefix,
+	}
+	if payload.Address {
+		resp.Address = a.address()
+	}
+
+	resp.FullMessage = FullMessage(resp)
+	sig, err := a.sign([]byte(resp.FullMessage))
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Signature = hexutil.Encode(sig)
+	return resp, nil
+}
+
+// FullMessage returns the message a wallet signs for resp:
+//
+//	APTOS
+//	address: <address>
+//	application: <application>
+//	chainId: <chainId>
+//	message: <message>
+//	nonce: <nonce>
+//
+// address, application and chainId are only present when set
+func FullMessage(resp *types.SignMessageResponse) string {
+	var b strings.Builder
+	b.WriteString(signMessagePrefix)
+	if resp.Address != "" {
+		b.WriteString(fmt.Sprintf("\naddress: %s", resp.Address))
+	}
+	if resp.Application != "" {
+		b.WriteString(fmt.Sprintf("\napplication: %s", resp.Application))
+	}
+	if resp.ChainId != 0 {
+		b.WriteString(fmt.Sprintf("\nchainId: %d", resp.ChainId))
+	}
+	b.WriteString(fmt.Sprintf("\nmessage: %s", resp.Message))
+	b.WriteString(fmt.Sprintf("\nnonce: %s", resp.Nonce))
+	return b.String()
+}
+
+// VerifySignedMessage verifies resp was signed by publicKey. The full message is rebuilt from the
+// response fields, so a signature can't be replayed with another nonce, application or chain.
+// Callers should check Nonce, Application and ChainId are the ones they expect
+func VerifySignedMessage(publicKey *types.AnyPublicKey, resp *types.SignMessageResponse) (bool, error) {
+	if resp.Prefix != signMessagePrefix {
+		return false, types.ErrMessagePrefix
+	}
+
+	if resp.FullMessage != FullMessage(resp) {
+		return false, types.ErrFullMessage
+	}
+
+	pubKey, err := hexutil.Decode(publicKey.Value)
+	if err != nil {
+		return false, err
+	}
+
+	sig, err := hexutil.Decode(resp.Signature)
+	if err != nil {
+		return false, err
+	}
+
+	msg := []byte(resp.FullMessage)
+	switch publicKey.Type {
+	case types.AnyEd25519:
+		if len(pubKey) != ed25519.PublicKeySize {
+			return false, types.ErrUnsupportedPublicKey
+		}
+		return ed25519.Verify(pubKey, msg, sig), nil
+	case types.Secp256k1Ecdsa:
+		return key_manager.VerifySecp256k1(pubKey, msg, sig), nil
+	default:
+		return false, types.ErrUnsupportedPublicKey
+	}
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+func TestAptAccount_SignMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		account *AptAccount
+		payload *types.SignMessagePayload
+	}{
+		{
+			name:    "ed25519 with every field",
+			account: NewAptAccount(mnemonic, ""),
+			payload: &types.SignMessagePayload{
+				Address:     true,
+				Application: "https://example.com",
+				ChainId:     1,
+				Message:     "Welcome to example.com",
+				Nonce:       "1234",
+			},
+		},
+		{
+			name:    "ed25519 message and nonce only",
+			account: NewAptAccount(mnemonic, ""),
+			payload: &types.SignMessagePayload{
+				Message: "Welcome to example.com",
+				Nonce:   "1234",
+			},
+		},
+		{
+			name:    "secp256k1",
+			account: NewSecp256k1Account(mnemonic, ""),
+			payload: &types.SignMessagePayload{
+				Address: true,
+				ChainId: 2,
+				Message: "Welcome to example.com",
+				Nonce:   "5678",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.account.SignMessage(tt.payload); err != types.ErrKeyNotLoaded {
+				t.Errorf("sign message before the account is loaded: %v", err)
+			}
+
+			_account, err := tt.account.GetAptAccount(0)
+			if err != nil {
+				t.Fatalf("get account for %s error: %s", tt.name, err)
+			}
+
+			resp, err := tt.account.SignMessage(tt.payload)
+			if err != nil {
+				t.Fatalf("sign message for %s error: %s", tt.name, err)
+			}
+			t.Logf("full message:\n%s", resp.FullMessage)
+
+			ok, err := VerifySignedMessage(AnyPublicKey(_account), resp)
+			if err != nil || !ok {
+				t.Errorf("verify signed message for %s: %v, %v", tt.name, ok, err)
+			}
+
+			resp.Nonce = "replayed"
+			if _, err = VerifySignedMessage(AnyPublicKey(_account), resp); err != types.ErrFullMessage {
+				t.Errorf("verify a replayed message for %s: %v", tt.name, err)
+			}
+
+			resp.FullMessage = FullMessage(resp)
+			if ok, _ = VerifySignedMessage(AnyPublicKey(_account), resp); ok {
+				t.Errorf("verified a replayed message for %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestAptClient_VerifySignedMessageByAddress(t *testing.T) {
+	account := NewAptAccount(mnemonic, "")
+	_account, err := account.GetAptAccount(0)
+	if err != nil {
+		t.Fatalf("get account error: %s", err)
+	}
+
+	// the node holds the key as a single key account
+	authKey, err := SingleKeyAuthKey(AnyPublicKey(_account))
+	if err != nil {
+		t.Fatalf("single key auth key error: %s", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"sequence_number": "0", "authentication_key": "%s"}`, authKey)
+	}))
+	defer server.Close()
+
+	c, err := NewAptClient(server.URL)
+	if err != nil {
+		t.Fatalf("new apt client error: %s", err)
+	}
+
+	resp, err := account.SignMessage(&types.SignMessagePayload{Address: true, Message: "Welcome to example.com", Nonce: "1234"})
+	if err != nil {
+		t.Fatalf("sign message error: %s", err)
+	}
+
+	long := strings.ToLower(resp.Address)
+	tests := []struct {
+		name    string
+		address string
+		want    bool
+		wantErr error
+	}{
+		{"same form", resp.Address, true, nil},
+		{"without 0x", strings.TrimPrefix(long, "0x"), true, nil},
+		{"leading zeros trimmed", "0x" + strings.TrimLeft(strings.TrimPrefix(long, "0x"), "0"), true, nil},
+		{"upper case", "0x" + strings.ToUpper(strings.TrimPrefix(long, "0x")), true, nil},
+		{"mismatched", "0x2", false, types.ErrAddressMismatched},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := c.VerifySignedMessageByAddress(types.MustParseAccountAddress(tt.address), AnyPublicKey(_account), resp)
+			if ok != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifySignedMessageByAddress() = %v, %v, want %v, %v", ok, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
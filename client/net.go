@@ -1,9 +1,11 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/deng00/req"
+	"net/http"
 	"strings"
 )
 
@@ -12,6 +14,15 @@ type Net struct {
 	Header map[string]string
 	Params map[string]interface{}
 	IsJson bool
+
+	// Ctx cancels the request when done, nil requests are not cancelable
+	Ctx context.Context
+
+	// Body is posted as is instead of Params, for BCS requests
+	Body []byte
+
+	// RespHeader is the header of the last response
+	RespHeader http.Header
 }
 
 type netType string
@@ -27,6 +38,16 @@ func NewNet(url string, header map[string]string, params map[string]interface{})
 	return &Net{Url: url, Header: header, Params: params}
 }
 
+func (n *Net) WithContext(ctx context.Context) *Net {
+	n.Ctx = ctx
+	return n
+}
+
+func (n *Net) WithBody(body []byte) *Net {
+	n.Body = body
+	return n
+}
+
 func (n *Net) Request(netType netType) (string, error) {
 	reqHeader, hasJson := n.initHeader()
 	reqParams := n.initParam()
@@ -58,34 +79,49 @@ func (n *Net) initParam() req.Param {
 }
 
 func (n *Net) get(header req.Header) (string, error) {
-	return checkResp(req.Get(n.Url, header))
+	return n.checkResp(req.Get(n.Url, n.args(header)...))
 }
 
 func (n *Net) post(header req.Header, param req.Param) (string, error) {
+	if n.Body != nil {
+		return n.checkResp(req.Post(n.Url, n.args(header, n.Body)...))
+	}
+
 	if n.IsJson {
 		jsonParam, _ := json.Marshal(param)
-		return checkResp(req.Post(n.Url, header, jsonParam))
+		return n.checkResp(req.Post(n.Url, n.args(header, jsonParam)...))
+	}
+	return n.checkResp(req.Post(n.Url, n.args(header, param)...))
+}
+
+// args appends Ctx to the request args of req
+func (n *Net) args(v ...interface{}) []interface{} {
+	if n.Ctx != nil {
+		v = append(v, n.Ctx)
 	}
-	return checkResp(req.Post(n.Url, header, param))
+	return v
 }
 
-func checkResp(res *req.Resp, err error) (string, error) {
+func (n *Net) checkResp(res *req.Resp, err error) (string, error) {
 	if err != nil || res == nil {
 		return "", fmt.Errorf("request rpc error: %s", err)
 	}
+	if r := res.Response(); r != nil {
+		n.RespHeader = r.Header
+	}
 	return res.String(), err
 }
 
 func (n *Net) delete(header req.Header) (string, error) {
-	return checkResp(req.Delete(n.Url, header))
+	return n.checkResp(req.Delete(n.Url, n.args(header)...))
 }
 
 func (n *Net) put(header req.Header, param req.Param) (string, error) {
 	if n.IsJson {
 		jsonParam, _ := json.Marshal(param)
-		return checkResp(req.Put(n.Url, header, jsonParam))
+		return n.checkResp(req.Put(n.Url, n.args(header, jsonParam)...))
 	}
-	return checkResp(req.Put(n.Url, header, param))
+	return n.checkResp(req.Put(n.Url, n.args(header, param)...))
 }
 
 func (n *Net) initHeader() (req.Header, bool) {
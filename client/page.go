@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+const (
+	// CursorHeader holds the start of the next page of resources or modules, absent on the last page
+	CursorHeader = "X-Aptos-Cursor"
+	// LedgerVersionHeader is the ledger version a response was read at
+	LedgerVersionHeader = "X-Aptos-Ledger-Version"
+)
+
+// AccountResourcesPage returns limit resources of address from cursor and the cursor of the next page,
+// empty on the last one. An empty cursor starts from the first resource, limit 0 is the node default
+func (a *AptClient) AccountResourcesPage(ctx context.Context, address types.AccountAddress, version, cursor string, limit uint16) ([]*types.AccountResource, string, error) {
+	var resources []*types.AccountResource
+	next, _, err := a.page(ctx, fmt.Sprintf("%s/accounts/%s/resources", a.rpc, address), version, cursor, limit, &resources)
+	return resources, next, err
+}
+
+// WalkAccountResources calls fn with each page of the resources of address until the last page or an
+// error of fn, pages after the first are read at the ledger version of the first so they are consistent
+func (a *AptClient) WalkAccountResources(ctx context.Context, address types.AccountAddress, version string, limit uint16, fn func([]*types.AccountResource) error) error {
+	rpc := fmt.Sprintf("%s/accounts/%s/resources", a.rpc, address)
+	return walkPages(ctx, a, rpc, version, limit, fn)
+}
+
+// AccountModulesPage returns limit modules of address from cursor and the cursor of the next page,
+// empty on the last one. An empty cursor starts from the first module, limit 0 is the node default
+func (a *AptClient) AccountModulesPage(ctx context.Context, address types.AccountAddress, version, cursor string, limit uint16) ([]*types.AccountModule, string, error) {
+	var modules []*types.AccountModule
+	next, _, err := a.page(ctx, fmt.Sprintf("%s/accounts/%s/modules", a.rpc, address), version, cursor, limit, &modules)
+	return modules, next, err
+}
+
+// WalkAccountModules calls fn with each page of the modules of address, like WalkAccountResources
+func (a *AptClient) WalkAccountModules(ctx context.Context, address types.AccountAddress, version string, limit uint16, fn func([]*types.AccountModule) error) error {
+	rpc := fmt.Sprintf("%s/accounts/%s/modules", a.rpc, address)
+	return walkPages(ctx, a, rpc, version, limit, fn)
+}
+
+func walkPages[T any](ctx context.Context, a *AptClient, rpc, version string, limit uint16, fn func([]T) error) error {
+	cursor := ""
+	for {
+		var items []T
+		next, ledgerVersion, err := a.page(ctx, rpc, version, cursor, limit, &items)
+		if err != nil {
+			return err
+		}
+
+		if err = fn(items); err != nil {
+			return err
+		}
+
+		if next == "" {
+			return nil
+		}
+		if version == "" {
+			version = ledgerVersion
+		}
+		cursor = next
+	}
+}
+
+// page gets a page of rpc into out and returns the next cursor and the ledger version it was read at
+func (a *AptClient) page(ctx context.Context, rpc, version, cursor string, limit uint16, out interface{}) (string, string, error) {
+	query := url.Values{}
+	if version != "" {
+		query.Set("ledger_version", version)
+	}
+	if cursor != "" {
+		query.Set("start", cursor)
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(int(limit)))
+	}
+	if len(query) > 0 {
+		rpc = rpc + "?" + query.Encode()
+	}
+
+	net := a.connClient(rpc, nil).WithContext(ctx)
+	req, err := net.Request(GetTy)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err = respErr(req); err != nil {
+		return "", "", err
+	}
+
+	if err = json.Unmarshal([]byte(req), out); err != nil {
+		return "", "", err
+	}
+	return net.RespHeader.Get(CursorHeader), net.RespHeader.Get(LedgerVersionHeader), nil
+}
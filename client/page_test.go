@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// testPagedNode serves 5 resources, 2 CoinStores among them, in pages of 2 at ledger version 10
+func testPagedNode(t *testing.T, versions *[]string) *httptest.Server {
+	resources := []string{
+		`{"type": "0x1::account::Account", "data": {"sequence_number": "0"}}`,
+		`{"type": "0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>", "data": {"coin": {"value": "100"}, "frozen": false}}`,
+		`{"type": "0x1::object::ObjectCore", "data": {}}`,
+		`{"type": "0x1::coin::CoinStore<0xcafe::m::C>", "data": {"coin": {"value": "7"}, "frozen": true}}`,
+		`{"type": "0xcafe::m::R", "data": {}}`,
+	}
+	cursors := map[string]int{"": 0, "0x0a": 2, "0x0b": 4}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/resources") {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		*versions = append(*versions, r.URL.Query().Get("ledger_version"))
+
+		start, ok := cursors[r.URL.Query().Get("start")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"message": "invalid cursor", "error_code": "invalid_input"}`)
+			return
+		}
+
+		end := start + 2
+		if end < len(resources) {
+			w.Header().Set(CursorHeader, map[int]string{2: "0x0a", 4: "0x0b"}[end])
+		} else {
+			end = len(resources)
+		}
+		w.Header().Set(LedgerVersionHeader, "10")
+		fmt.Fprintf(w, "[%s]", strings.Join(resources[start:end], ","))
+	}))
+}
+
+func TestAccountResourcesPaging(t *testing.T) {
+	var versions []string
+	server := testPagedNode(t, &versions)
+	defer server.Close()
+
+	c, err := NewAptClient(server.URL)
+	if err != nil {
+		t.Fatalf("NewAptClient() error = %v", err)
+	}
+
+	owner := types.MustParseAccountAddress("0x5")
+	page, cursor, err := c.AccountResourcesPage(context.Background(), owner, "", "", 2)
+	if err != nil || len(page) != 2 || cursor != "0x0a" {
+		t.Fatalf("AccountResourcesPage() = %d, %q, %v", len(page), cursor, err)
+	}
+	if page, cursor, err = c.AccountResourcesPage(context.Background(), owner, "", "0x0b", 2); err != nil || len(page) != 1 || cursor != "" {
+		t.Errorf("AccountResourcesPage() of the last page = %d, %q, %v", len(page), cursor, err)
+	}
+	if _, _, err = c.AccountResourcesPage(context.Background(), owner, "", "0xff", 2); err == nil {
+		t.Errorf("AccountResourcesPage() of a bad cursor error = nil")
+	}
+
+	// pages after the first are pinned to the ledger version of the first
+	versions = nil
+	resources, err := c.AccountResources(owner, "")
+	if err != nil || len(resources) != 5 || resources[4].Type != "0xcafe::m::R" {
+		t.Fatalf("AccountResources() = %d, %v", len(resources), err)
+	}
+	if fmt.Sprintf("%q", versions) != `["" "10" "10"]` {
+		t.Errorf("ledger versions = %q", versions)
+	}
+
+	stop := errors.New("stop")
+	pages := 0
+	err = c.WalkAccountResources(context.Background(), owner, "3", 2, func([]*types.AccountResource) error {
+		pages++
+		return stop
+	})
+	if !errors.Is(err, stop) || pages != 1 {
+		t.Errorf("WalkAccountResources() = %d pages, %v, want 1, %v", pages, err, stop)
+	}
+
+	balances, err := c.CoinBalances(owner)
+	if err != nil || len(balances) != 2 {
+		t.Fatalf("CoinBalances() = %d, %v", len(balances), err)
+	}
+	if balances[0].CoinType != types.AptCoinTy || balances[0].Value.Int64() != 100 || balances[1].CoinType != "0xcafe::m::C" || !balances[1].Frozen {
+		t.Errorf("CoinBalances() = %+v, %+v", balances[0], balances[1])
+	}
+}
@@ -0,0 +1,236 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/threeandtwo/aptclient/bcs"
+	"github.com/threeandtwo/aptclient/bytecode"
+	"github.com/threeandtwo/aptclient/hexutil"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+const (
+	PublishPackageFunc    = "0x1::code::publish_package_txn"
+	ObjectCodePublishFunc = "0x1::object_code_deployment::publish"
+	ObjectCodeUpgradeFunc = "0x1::object_code_deployment::upgrade"
+
+	// MaxPublishPackageSize is the limit of metadata and bytecode `aptos move publish` sends in one
+	// transaction, larger packages are published in chunks
+	MaxPublishPackageSize = 60000
+
+	// ObjectCodeDeploymentSeed is the domain separator of the seed of objects object_code_deployment publishes to
+	ObjectCodeDeploymentSeed = "aptos_framework::object_code_deployment"
+
+	PackageMetadataFile = "package-metadata.bcs"
+	BytecodeModulesDir  = "bytecode_modules"
+)
+
+// Package is a compiled Move package, the BCS PackageMetadata and the bytecode of its modules
+// in dependency order
+type Package struct {
+	Metadata []byte
+	Modules  [][]byte
+}
+
+// Size is the number of metadata and bytecode bytes the package publishes
+func (p *Package) Size() int {
+	size := len(p.Metadata)
+	for _, m := range p.Modules {
+		size += len(m)
+	}
+	return size
+}
+
+// LoadPackage reads the artifacts of `aptos move compile --save-metadata` in dir, the build/<package>
+// directory holding package-metadata.bcs and bytecode_modules/*.mv, dependencies are skipped and the
+// modules are ordered by their dependencies on each other
+func LoadPackage(dir string) (*Package, error) {
+	metadata, err := os.ReadFile(filepath.Join(dir, PackageMetadataFile))
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, BytecodeModulesDir, "*.mv"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	modules := make([][]byte, 0, len(files))
+	for _, file := range files {
+		code, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, code)
+	}
+
+	if modules, err = SortModules(modules); err != nil {
+		return nil, err
+	}
+	return &Package{Metadata: metadata, Modules: modules}, nil
+}
+
+// SortModules orders the bytecode of modules so every module follows the modules it uses,
+// the order of independent modules is kept
+func SortModules(modules [][]byte) ([][]byte, error) {
+	ids := make([]string, 0, len(modules))
+	index := make(map[string]int, len(modules))
+	deps := make([][]string, 0, len(modules))
+	for i, code := range modules {
+		m, err := bytecode.Parse(code)
+		if err != nil {
+			return nil, fmt.Errorf("module %d: %w", i, err)
+		}
+		ids = append(ids, m.ID())
+		index[m.ID()] = i
+		deps = append(deps, m.Dependencies())
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(modules))
+	sorted := make([][]byte, 0, len(modules))
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %s", types.ErrModuleCycle, ids[i])
+		}
+
+		state[i] = visiting
+		for _, dep := range deps[i] {
+			if j, ok := index[dep]; ok {
+				if err := visit(j); err != nil {
+					return err
+				}
+			}
+		}
+		state[i] = visited
+		sorted = append(sorted, modules[i])
+		return nil
+	}
+
+	for i := range modules {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// PublishPackagePayload publishes pkg at the sender address by code::publish_package_txn
+func PublishPackagePayload(pkg *Package) (*types.EntryFunctionPayload, error) {
+	return publishPayload(PublishPackageFunc, pkg)
+}
+
+// ObjectCodePublishPayload publishes pkg to a new object by object_code_deployment::publish,
+// the object address is ObjectCodeAddress of the sender and the sequence number of the transaction
+func ObjectCodePublishPayload(pkg *Package) (*types.EntryFunctionPayload, error) {
+	return publishPayload(ObjectCodePublishFunc, pkg)
+}
+
+// ObjectCodeUpgradePayload upgrades the package published to codeObject by object_code_deployment::upgrade,
+// the sender must own codeObject
+func ObjectCodeUpgradePayload(pkg *Package, codeObject string) (*types.EntryFunctionPayload, error) {
+	codeObject, err := checkAccount(codeObject)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := publishPayload(ObjectCodeUpgradeFunc, pkg)
+	if err != nil {
+		return nil, err
+	}
+	payload.Arguments = append(payload.Arguments, codeObject)
+	return payload, nil
+}
+
+func publishPayload(function string, pkg *Package) (*types.EntryFunctionPayload, error) {
+	if pkg == nil || len(pkg.Modules) == 0 {
+		return nil, types.ErrPackageEmpty
+	}
+
+	if size := pkg.Size(); size > MaxPublishPackageSize {
+		return nil, fmt.Errorf("%w: %d bytes", types.ErrPackageTooLarge, size)
+	}
+
+	modules := make([]interface{}, 0, len(pkg.Modules))
+	for _, code := range pkg.Modules {
+		modules = append(modules, hexutil.Encode(code))
+	}
+
+	return &types.EntryFunctionPayload{
+		Type:          types.EntryFunctionPayloadTy,
+		Function:      function,
+		TypeArguments: []string{},
+		Arguments:     []interface{}{hexutil.Encode(pkg.Metadata), modules},
+	}, nil
+}
+
+// ObjectCodeAddress returns the object object_code_deployment::publish creates when publisher sends it
+// with sequenceNumber, the named object of publisher and bcs(ObjectCodeDeploymentSeed) || bcs(sequenceNumber+1)
+func ObjectCodeAddress(publisher string, sequenceNumber uint64) (string, error) {
+	s := bcs.NewSerializer()
+	s.WriteStr(ObjectCodeDeploymentSeed)
+	s.U64(sequenceNumber + 1)
+	if err := s.Error(); err != nil {
+		return "", err
+	}
+	return CreateObjectAddress(publisher, s.ToBytes())
+}
+
+// PublishPackage publishes pkg at the address of account, publishing usually needs a larger
+// TxOptions.MaxGasAmount than DefaultMaxGasAmount
+func (a *AptClient) PublishPackage(account *types.AptAccount, pkg *Package, opts *TxOptions) (*types.Transaction, error) {
+	payload, err := PublishPackagePayload(pkg)
+	if err != nil {
+		return nil, err
+	}
+	return a.SubmitPayload(account, payload, opts)
+}
+
+// PublishPackageToObject publishes pkg to a new object owned by account and returns the object address
+// with the pending transaction, the modules of pkg must be compiled for that address
+func (a *AptClient) PublishPackageToObject(account *types.AptAccount, pkg *Package, opts *TxOptions) (*types.Transaction, string, error) {
+	payload, err := ObjectCodePublishPayload(pkg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sender, err := types.ParseAccountAddress(account.Address)
+	if err != nil {
+		return nil, "", err
+	}
+
+	unsignedTx, err := a.BuildTransaction(sender, payload, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	codeObject, err := ObjectCodeAddress(account.Address, unsignedTx.SequenceNumber)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tx, err := a.SignAndSubmit(account, unsignedTx)
+	return tx, codeObject, err
+}
+
+// UpgradeObjectPackage upgrades the package account published to codeObject
+func (a *AptClient) UpgradeObjectPackage(account *types.AptAccount, pkg *Package, codeObject types.AccountAddress, opts *TxOptions) (*types.Transaction, error) {
+	payload, err := ObjectCodeUpgradePayload(pkg, codeObject.String())
+	if err != nil {
+		return nil, err
+	}
+	return a.SubmitPayload(account, payload, opts)
+}
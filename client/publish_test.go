@@ -0,0 +1,172 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/threeandtwo/aptclient/bcs"
+	"github.com/threeandtwo/aptclient/bytecode"
+	"github.com/threeandtwo/aptclient/hexutil"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// testModuleCode returns the bytecode of an empty module 0x1::name using 0x1::deps
+func testModuleCode(name string, deps ...string) []byte {
+	identifiers := bcs.NewSerializer()
+	handles := bcs.NewSerializer()
+	for i, id := range append([]string{name}, deps...) {
+		identifiers.WriteStr(id)
+		handles.Uleb128(0)
+		handles.Uleb128(uint32(i))
+	}
+
+	tables := []struct {
+		kind uint8
+		b    []byte
+	}{{0x1, handles.ToBytes()}, {0x7, identifiers.ToBytes()}, {0x8, types.AccountOne[:]}}
+
+	s := bcs.NewSerializer()
+	s.FixedBytes([]byte{0xA1, 0x1C, 0xEB, 0x0B})
+	s.U32(6)
+	s.Uleb128(uint32(len(tables)))
+	offset := 0
+	for _, t := range tables {
+		s.U8(t.kind)
+		s.Uleb128(uint32(offset))
+		s.Uleb128(uint32(len(t.b)))
+		offset += len(t.b)
+	}
+	for _, t := range tables {
+		s.FixedBytes(t.b)
+	}
+	s.Uleb128(0)
+	return s.ToBytes()
+}
+
+func TestLoadPackage(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, BytecodeModulesDir, "dependencies"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string][]byte{
+		PackageMetadataFile:                                          {0x01, 0x02},
+		filepath.Join(BytecodeModulesDir, "a.mv"):                    testModuleCode("a", "c"),
+		filepath.Join(BytecodeModulesDir, "b.mv"):                    testModuleCode("b"),
+		filepath.Join(BytecodeModulesDir, "c.mv"):                    testModuleCode("c", "b", "coin"),
+		filepath.Join(BytecodeModulesDir, "dependencies", "coin.mv"): testModuleCode("coin"),
+	}
+	for name, b := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pkg, err := LoadPackage(dir)
+	if err != nil {
+		t.Fatalf("LoadPackage() error = %v", err)
+	}
+
+	var names []string
+	for _, code := range pkg.Modules {
+		m, err := bytecode.Parse(code)
+		if err != nil {
+			t.Fatalf("bytecode.Parse() error = %v", err)
+		}
+		names = append(names, m.Name())
+	}
+	if len(names) != 3 || names[0] != "b" || names[1] != "c" || names[2] != "a" {
+		t.Errorf("LoadPackage() modules = %v, want [b c a]", names)
+	}
+
+	if _, err = SortModules([][]byte{testModuleCode("a", "b"), testModuleCode("b", "a")}); !errors.Is(err, types.ErrModuleCycle) {
+		t.Errorf("SortModules() error = %v, want %v", err, types.ErrModuleCycle)
+	}
+}
+
+func TestPublishPackagePayload(t *testing.T) {
+	pkg := &Package{Metadata: []byte{0x01}, Modules: [][]byte{{0xa1, 0x1c}, {0xeb, 0x0b}}}
+
+	tests := []struct {
+		name     string
+		payload  func() (*types.EntryFunctionPayload, error)
+		function string
+		args     int
+		wantErr  error
+	}{
+		{
+			name:     "publish package",
+			payload:  func() (*types.EntryFunctionPayload, error) { return PublishPackagePayload(pkg) },
+			function: PublishPackageFunc,
+			args:     2,
+		},
+		{
+			name:     "object code publish",
+			payload:  func() (*types.EntryFunctionPayload, error) { return ObjectCodePublishPayload(pkg) },
+			function: ObjectCodePublishFunc,
+			args:     2,
+		},
+		{
+			name:     "object code upgrade",
+			payload:  func() (*types.EntryFunctionPayload, error) { return ObjectCodeUpgradePayload(pkg, "0xabc") },
+			function: ObjectCodeUpgradeFunc,
+			args:     3,
+		},
+		{
+			name:    "empty package",
+			payload: func() (*types.EntryFunctionPayload, error) { return PublishPackagePayload(&Package{}) },
+			wantErr: types.ErrPackageEmpty,
+		},
+		{
+			name: "too large",
+			payload: func() (*types.EntryFunctionPayload, error) {
+				return PublishPackagePayload(&Package{Modules: [][]byte{make([]byte, MaxPublishPackageSize+1)}})
+			},
+			wantErr: types.ErrPackageTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := tt.payload()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("payload error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if payload.Function != tt.function || len(payload.Arguments) != tt.args {
+				t.Fatalf("payload = %+v", payload)
+			}
+
+			modules, ok := payload.Arguments[1].([]interface{})
+			if payload.Arguments[0] != "0x01" || !ok || len(modules) != 2 || modules[0] != "0xa11c" || modules[1] != "0xeb0b" {
+				t.Errorf("payload arguments = %v", payload.Arguments)
+			}
+		})
+	}
+}
+
+func TestObjectCodeAddress(t *testing.T) {
+	publisher := "0x5a"
+	got, err := ObjectCodeAddress(publisher, 4)
+	if err != nil {
+		t.Fatalf("ObjectCodeAddress() error = %v", err)
+	}
+
+	// bcs(b"aptos_framework::object_code_deployment") || bcs(5u64)
+	seed := append([]byte{39}, []byte("aptos_framework::object_code_deployment")...)
+	seed = append(seed, 5, 0, 0, 0, 0, 0, 0, 0)
+	want, _ := CreateObjectAddress(publisher, seed)
+	if got != want {
+		t.Errorf("ObjectCodeAddress() = %v, want %v", got, want)
+	}
+
+	if b, _ := hexutil.Decode(got); len(b) != types.AccountAddressLength || bytes.Equal(b, make([]byte, types.AccountAddressLength)) {
+		t.Errorf("ObjectCodeAddress() = %v", got)
+	}
+}
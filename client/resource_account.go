@@ -0,0 +1,79 @@
+package client
+
+import (
+	"github.com/threeandtwo/aptclient/bcs"
+	"github.com/threeandtwo/aptclient/hexutil"
+	"github.com/threeandtwo/aptclient/key_manager"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+const (
+	CreateResourceAccountFunc                  = "0x1::resource_account::create_resource_account"
+	CreateResourceAccountAndPublishPackageFunc = "0x1::resource_account::create_resource_account_and_publish_package"
+)
+
+// CreateResourceAddress returns sha3-256(source || seed || 0xFF), the address of
+// account::create_resource_address(source, seed)
+func CreateResourceAddress(source string, seed []byte) (string, error) {
+	return deriveAddress(source, key_manager.ResourceAccountScheme, seed)
+}
+
+// CreateObjectAddress returns sha3-256(creator || seed || 0xFE), the address of
+// object::create_named_object(creator, seed)
+func CreateObjectAddress(creator string, seed []byte) (string, error) {
+	return deriveAddress(creator, key_manager.ObjectFromSeedScheme, seed)
+}
+
+// CreateObjectAddressFromGuid returns sha3-256(bcs(GUID) || 0xFD), the address of
+// object::create_object_from_account, creationNum is the guid_creation_num of creator before the call
+func CreateObjectAddressFromGuid(creator string, creationNum uint64) (string, error) {
+	address, err := types.ParseAccountAddress(creator)
+	if err != nil {
+		return "", err
+	}
+
+	// guid::ID is {creation_num: u64, addr: address}
+	s := bcs.NewSerializer()
+	s.U64(creationNum)
+	s.Struct(&address)
+	if err = s.Error(); err != nil {
+		return "", err
+	}
+	return hexutil.Encode(key_manager.AuthKey(key_manager.ObjectFromGuidScheme, s.ToBytes())), nil
+}
+
+func deriveAddress(source string, scheme key_manager.AuthScheme, seed []byte) (string, error) {
+	address, err := types.ParseAccountAddress(source)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(key_manager.AuthKey(scheme, address.Bytes(), seed)), nil
+}
+
+// CreateResourceAccountPayload creates the resource account of the sender and seed,
+// an empty optionalAuthKey rotates its auth key to the sender's
+func CreateResourceAccountPayload(seed, optionalAuthKey []byte) *types.EntryFunctionPayload {
+	return &types.EntryFunctionPayload{
+		Type:          types.EntryFunctionPayloadTy,
+		Function:      CreateResourceAccountFunc,
+		TypeArguments: []string{},
+		Arguments:     []interface{}{hexutil.Encode(seed), hexutil.Encode(optionalAuthKey)},
+	}
+}
+
+// CreateResourceAccountAndPublishPackagePayload creates the resource account of the sender and seed
+// and publishes the package there, metadata and code are the BCS package metadata and module bytecode
+// of `aptos move build-publish-payload`
+func CreateResourceAccountAndPublishPackagePayload(seed, metadata []byte, code [][]byte) *types.EntryFunctionPayload {
+	modules := make([]interface{}, 0, len(code))
+	for _, c := range code {
+		modules = append(modules, hexutil.Encode(c))
+	}
+
+	return &types.EntryFunctionPayload{
+		Type:          types.EntryFunctionPayloadTy,
+		Function:      CreateResourceAccountAndPublishPackageFunc,
+		TypeArguments: []string{},
+		Arguments:     []interface{}{hexutil.Encode(seed), hexutil.Encode(metadata), modules},
+	}
+}
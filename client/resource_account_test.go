@@ -0,0 +1,66 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+func TestDeriveAddress(t *testing.T) {
+	creator := "0x5792c985bc96f436270bd2a3c692210b09c7febb8889345ceefdbae4bacfe498"
+
+	tests := []struct {
+		name    string
+		derive  func() (string, error)
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "resource account",
+			derive: func() (string, error) { return CreateResourceAddress(creator, []byte("seed")) },
+			want:   "0x647ab4c0c84090bc6c0c652f39ea4ad727837bd704af228b55d987f750332029",
+		},
+		{
+			name:   "named object",
+			derive: func() (string, error) { return CreateObjectAddress(creator, []byte("seed")) },
+			want:   "0x0e2599913c34dd66804d2f8698ccbea9eaff6682fd1e9bedbdb78dabb7054923",
+		},
+		{
+			name:   "object from guid",
+			derive: func() (string, error) { return CreateObjectAddressFromGuid(creator, 5) },
+			want:   "0x39d0e09a2932de3e84769807422793bd12ddab8af76a9d0cc2ee39347863aa3e",
+		},
+		{
+			name:    "invalid source",
+			derive:  func() (string, error) { return CreateResourceAddress("0xz", []byte("seed")) },
+			wantErr: types.ErrAddressHex,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.derive()
+			if err != tt.wantErr {
+				t.Errorf("derive error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("derive = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateResourceAccountAndPublishPackagePayload(t *testing.T) {
+	payload := CreateResourceAccountAndPublishPackagePayload([]byte("seed"), []byte{0x01}, [][]byte{{0xa1, 0x1c}, {0xeb}})
+	b, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	want := `{"type":"entry_function_payload","function":"0x1::resource_account::create_resource_account_and_publish_package","type_arguments":[],"arguments":["0x73656564","0x01",["0xa11c","0xeb"]]}`
+	if string(b) != want {
+		t.Errorf("payload = %s, want %s", b, want)
+	}
+}
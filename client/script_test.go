@@ -0,0 +1,144 @@
+package client
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/threeandtwo/aptclient/bcs"
+	"github.com/threeandtwo/aptclient/types"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []*types.ScriptArgument
+		wantArgs string
+		wantJSON string
+		wantErr  error
+	}{
+		{
+			name: "primitive arguments",
+			args: []*types.ScriptArgument{
+				{Type: types.ScriptArgU64, Value: uint64(7)},
+				{Type: types.ScriptArgAddress, Value: types.AccountOne},
+				{Type: types.ScriptArgBool, Value: true},
+			},
+			wantArgs: "03" + "010700000000000000" + "03" + "0000000000000000000000000000000000000000000000000000000000000001" + "0501",
+			wantJSON: `{"type":"script_payload","code":{"bytecode":"0xa11c"},"type_arguments":["0x1::aptos_coin::AptosCoin"],"arguments":["7","0x1",true]}`,
+		},
+		{
+			name: "wide and vector arguments",
+			args: []*types.ScriptArgument{
+				{Type: types.ScriptArgU8, Value: uint8(1)},
+				{Type: types.ScriptArgU16, Value: uint16(2)},
+				{Type: types.ScriptArgU32, Value: uint32(3)},
+				{Type: types.ScriptArgU128, Value: big.NewInt(4)},
+				{Type: types.ScriptArgU8Vector, Value: []byte{0xab}},
+			},
+			wantArgs: "05" + "0001" + "060200" + "0703000000" + "0204000000000000000000000000000000" + "0401ab",
+			wantJSON: `{"type":"script_payload","code":{"bytecode":"0xa11c"},"type_arguments":["0x1::aptos_coin::AptosCoin"],"arguments":[1,2,3,"4","0xab"]}`,
+		},
+		{
+			name:    "mismatched value",
+			args:    []*types.ScriptArgument{{Type: types.ScriptArgU64, Value: 7}},
+			wantErr: types.ErrScriptArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, err := types.NewScript([]byte{0xa1, 0x1c}, []string{"0x1::aptos_coin::AptosCoin"}, tt.args)
+			if err != nil {
+				t.Fatalf("NewScript() error = %v", err)
+			}
+
+			b, err := bcs.Serialize(script)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("bcs.Serialize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, jsonErr := json.Marshal(script); !errors.Is(jsonErr, tt.wantErr) {
+				t.Fatalf("json.Marshal() error = %v, wantErr %v", jsonErr, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			// code || one type argument || arguments
+			tag, _ := bcs.Serialize(script.TypeArgs[0])
+			want := "02a11c" + "01" + hex.EncodeToString(tag) + tt.wantArgs
+			if got := hex.EncodeToString(b); got != want {
+				t.Errorf("bcs.Serialize() = %s, want %s", got, want)
+			}
+
+			decoded := &types.Script{}
+			if err = bcs.Deserialize(decoded, b); err != nil {
+				t.Fatalf("bcs.Deserialize() error = %v", err)
+			}
+			if again, _ := bcs.Serialize(decoded); !bytes.Equal(again, b) {
+				t.Errorf("bcs.Deserialize() = %x, want %x", again, b)
+			}
+
+			if got, _ := json.Marshal(script); string(got) != tt.wantJSON {
+				t.Errorf("json.Marshal() = %s, want %s", got, tt.wantJSON)
+			}
+		})
+	}
+}
+
+func TestSignRawTransaction(t *testing.T) {
+	account, err := NewAptAccount(mnemonic, "").AccountFromMnemonic(0)
+	if err != nil {
+		t.Fatalf("account error: %s", err)
+	}
+
+	script, err := types.NewScript([]byte{0xa1, 0x1c}, nil, []*types.ScriptArgument{{Type: types.ScriptArgU64, Value: uint64(1)}})
+	if err != nil {
+		t.Fatalf("NewScript() error = %v", err)
+	}
+
+	unsignedTx := &types.UnsignedTx{Sender: account.Address, SequenceNumber: 3, MaxGasAmount: 2000, GasUnitPrice: 100, ExpirationTime: 1700000000, Payload: script}
+	raw, err := types.NewRawTransaction(unsignedTx, 2)
+	if err != nil {
+		t.Fatalf("NewRawTransaction() error = %v", err)
+	}
+
+	b, err := bcs.Serialize(raw)
+	if err != nil {
+		t.Fatalf("bcs.Serialize() error = %v", err)
+	}
+	decoded := &types.RawTransaction{}
+	if err = bcs.Deserialize(decoded, b); err != nil {
+		t.Fatalf("bcs.Deserialize() error = %v", err)
+	}
+	if again, _ := bcs.Serialize(decoded); !bytes.Equal(again, b) || b[len(b)-1] != 2 || b[40] != 0 {
+		t.Errorf("raw transaction = %x", b)
+	}
+
+	signedTx, err := SignRawTransaction(account, raw)
+	if err != nil {
+		t.Fatalf("SignRawTransaction() error = %v", err)
+	}
+
+	salt := sha3.Sum256([]byte(types.RawTransactionSalt))
+	sig, _ := hex.DecodeString(signedTx.Signature.Signature)
+	publicKey, _ := hex.DecodeString(strings.TrimPrefix(signedTx.Signature.PublicKey, "0x"))
+	if !ed25519.Verify(publicKey, append(salt[:], b...), sig) {
+		t.Errorf("SignRawTransaction() signature does not verify")
+	}
+
+	if _, err = bcs.Serialize(signedTx); err != nil {
+		t.Errorf("bcs.Serialize() signed transaction error = %v", err)
+	}
+
+	unsignedTx.Payload = &types.EntryFunctionPayload{Type: types.EntryFunctionPayloadTy}
+	if _, err = types.NewRawTransaction(unsignedTx, 2); !errors.Is(err, types.ErrPayloadBCS) {
+		t.Errorf("NewRawTransaction() error = %v, want %v", err, types.ErrPayloadBCS)
+	}
+}
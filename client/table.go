@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/threeandtwo/aptclient/bcs"
+	"github.com/threeandtwo/aptclient/hexutil"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+const BCSContentType = "application/x-bcs"
+
+// TableItem decodes the value of key in the table handle at ledgerVersion, the latest when empty,
+// into out like DecodeViewValues, key is converted by MoveArgument and the error wraps
+// types.ErrTableItemNotFound when key is absent
+func (a *AptClient) TableItem(ctx context.Context, handle, keyType, valueType string, key interface{}, ledgerVersion string, out interface{}) error {
+	if handle == "" || keyType == "" || valueType == "" {
+		return types.ErrTableItemNull
+	}
+
+	k, err := MoveArgument(key)
+	if err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{
+		"key_type":   keyType,
+		"value_type": valueType,
+		"key":        k,
+	}
+
+	req, err := a.connClient(a.tableRpc(handle, "item", ledgerVersion), params).WithContext(ctx).Request(PostTy)
+	if err != nil {
+		return err
+	}
+
+	if err = respErr(req); err != nil {
+		return err
+	}
+	return decodeMoveValue(json.RawMessage(req), out)
+}
+
+// RawTableItem returns the BCS value of the BCS key in the table handle, for tables whose key
+// and value types are not known or have no JSON form
+func (a *AptClient) RawTableItem(ctx context.Context, handle string, key []byte, ledgerVersion string) ([]byte, error) {
+	if handle == "" || key == nil {
+		return nil, types.ErrTableItemNull
+	}
+
+	params := map[string]interface{}{"key": hexutil.Encode(key)}
+	header := initHeader()
+	header["accept"] = BCSContentType
+
+	req, err := NewNet(a.tableRpc(handle, "raw_item", ledgerVersion), header, params).WithContext(ctx).Request(PostTy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = respErr(req); err != nil {
+		return nil, err
+	}
+	return []byte(req), nil
+}
+
+// RawTableItemInto is RawTableItem deserializing the value into out
+func (a *AptClient) RawTableItemInto(ctx context.Context, handle string, key []byte, ledgerVersion string, out bcs.Unmarshaler) error {
+	b, err := a.RawTableItem(ctx, handle, key, ledgerVersion)
+	if err != nil {
+		return err
+	}
+	return bcs.Deserialize(out, b)
+}
+
+func (a *AptClient) tableRpc(handle, item, ledgerVersion string) string {
+	if ledgerVersion == "" {
+		return fmt.Sprintf("%s/tables/%s/%s", a.rpc, handle, item)
+	}
+	return fmt.Sprintf("%s/tables/%s/%s?ledger_version=%s", a.rpc, handle, item, ledgerVersion)
+}
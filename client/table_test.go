@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/threeandtwo/aptclient/bcs"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+type u128Value struct {
+	v *big.Int
+}
+
+func (u *u128Value) UnmarshalBCS(d *bcs.Deserializer) {
+	u.v = d.U128()
+}
+
+func TestAptClient_TableItem(t *testing.T) {
+	c, err := NewAptClient(MAINNET_RPC_ADDR)
+	if err != nil {
+		t.Logf("new apt client error: %s", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err = c.TableItem(ctx, "", "address", "u128", "0x1", "", new(big.Int)); err != types.ErrTableItemNull {
+		t.Errorf("TableItem() error = %v, want %v", err, types.ErrTableItemNull)
+	}
+
+	res, err := c.AccountResourceByType(types.AccountOne, "0x1::coin::CoinInfo<0x1::aptos_coin::AptosCoin>", "")
+	if err != nil {
+		t.Logf("coin info error: %s", err)
+		return
+	}
+
+	var info types.CoinInfoData
+	if err = decodeResource(res, &info); err != nil || len(info.Supply.Vec) == 0 || len(info.Supply.Vec[0].Aggregator.Vec) == 0 {
+		t.Logf("apt supply is not an aggregator: %v", err)
+		return
+	}
+	aggregator := info.Supply.Vec[0].Aggregator.Vec[0]
+
+	supply := new(big.Int)
+	if err = c.TableItem(ctx, aggregator.Handle, "address", "u128", aggregator.Key, "", supply); err != nil {
+		t.Logf("table item error: %s", err)
+		return
+	}
+	t.Logf("apt supply: %s", supply)
+
+	key, err := types.ParseAccountAddress(aggregator.Key)
+	if err != nil {
+		t.Errorf("aggregator key error: %s", err)
+		return
+	}
+
+	var raw u128Value
+	if err = c.RawTableItemInto(ctx, aggregator.Handle, key.Bytes(), "", &raw); err != nil {
+		t.Logf("raw table item error: %s", err)
+		return
+	}
+	t.Logf("apt supply by raw item: %s", raw.v)
+}
@@ -0,0 +1,211 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+const testUserTransaction = `{
+  "type": "user_transaction",
+  "version": "1234",
+  "hash": "0xabc",
+  "state_change_hash": "0x1",
+  "event_root_hash": "0x2",
+  "state_checkpoint_hash": null,
+  "gas_used": "12",
+  "success": true,
+  "vm_status": "Executed successfully",
+  "accumulator_root_hash": "0x3",
+  "changes": [
+    {"type": "write_resource", "address": "0x5", "state_key_hash": "0x4", "data": {"type": "0x1::account::Account", "data": {"sequence_number": "8"}}},
+    {"type": "delete_resource", "address": "0x5", "state_key_hash": "0x6", "resource": "0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>"},
+    {"type": "write_module", "address": "0x5", "state_key_hash": "0x7", "data": {"bytecode": "0xa11ceb0b", "abi": {"address": "0x5", "name": "demo"}}},
+    {"type": "write_table_item", "state_key_hash": "0x8", "handle": "0x9", "key": "0x01", "value": "0x02", "data": {"key": "1", "key_type": "u64", "value": "2", "value_type": "u64"}},
+    {"type": "delete_table_item", "state_key_hash": "0xa", "handle": "0x9", "key": "0x03", "data": null}
+  ],
+  "sender": "0x5",
+  "sequence_number": "7",
+  "max_gas_amount": "2000",
+  "gas_unit_price": "100",
+  "expiration_timestamp_secs": "1700000000",
+  "payload": {"type": "entry_function_payload", "function": "0x1::aptos_account::transfer", "type_arguments": [], "arguments": ["0x6", "100"]},
+  "signature": {
+    "type": "fee_payer_signature",
+    "sender": {"type": "ed25519_signature", "public_key": "0xaa", "signature": "0xbb"},
+    "secondary_signer_addresses": [],
+    "secondary_signers": [],
+    "fee_payer_address": "0x7",
+    "fee_payer_signer": {"type": "single_key_signature", "public_key": {"type": "ed25519", "value": "0xcc"}, "signature": {"type": "ed25519", "value": "0xdd"}}
+  },
+  "events": [{"guid": {"creation_number": "0", "account_address": "0x0"}, "sequence_number": "0", "type": "0x1::transaction_fee::FeeStatement", "data": {}}],
+  "timestamp": "1699999999000000"
+}`
+
+const testBlockMetadataTransaction = `{
+  "type": "block_metadata_transaction",
+  "version": "1233",
+  "hash": "0xdef",
+  "gas_used": "0",
+  "success": true,
+  "vm_status": "Executed successfully",
+  "changes": [],
+  "id": "0x11",
+  "epoch": "9",
+  "round": "10",
+  "events": [],
+  "previous_block_votes_bitvec": [255, 1],
+  "proposer": "0x12",
+  "failed_proposer_indices": [3],
+  "timestamp": "1699999998000000"
+}`
+
+func TestTransactionTyped(t *testing.T) {
+	var tx types.Transaction
+	if err := json.Unmarshal([]byte(testUserTransaction), &tx); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	typed, err := tx.Typed()
+	if err != nil {
+		t.Fatalf("Typed() error = %v", err)
+	}
+
+	user, ok := typed.User()
+	if !ok || typed.Type != types.UserTransactionTy {
+		t.Fatalf("User() = %v, %v", user, ok)
+	}
+	if _, ok = typed.Pending(); ok {
+		t.Errorf("Pending() of a user transaction = true")
+	}
+	if user.Version != 1234 || user.SequenceNumber != 7 || user.GasUsed != 12 || user.Timestamp != 1699999999000000 || len(typed.Events()) != 1 {
+		t.Errorf("User() = %+v", user)
+	}
+
+	payload, ok := user.Payload.EntryFunction()
+	if !ok || payload.Function != "0x1::aptos_account::transfer" || len(payload.Arguments) != 2 {
+		t.Errorf("EntryFunction() = %+v, %v", payload, ok)
+	}
+	if _, ok = user.Payload.Script(); ok {
+		t.Errorf("Script() of an entry function payload = true")
+	}
+
+	sig, ok := user.Signature.MultiAgent()
+	if !ok || sig.FeePayerAddress != "0x7" {
+		t.Fatalf("MultiAgent() = %+v, %v", sig, ok)
+	}
+	if sender, ok := sig.Sender.Ed25519(); !ok || sender.PublicKey != "0xaa" {
+		t.Errorf("Ed25519() = %+v, %v", sender, ok)
+	}
+	if feePayer, ok := sig.FeePayerSigner.AnyKey(); !ok || feePayer.PublicKey.Value != "0xcc" || feePayer.Signature.Value != "0xdd" {
+		t.Errorf("AnyKey() = %+v, %v", feePayer, ok)
+	}
+
+	changes := user.Changes
+	if len(changes) != 5 {
+		t.Fatalf("Changes = %d, want 5", len(changes))
+	}
+	if res, ok := changes[0].WriteResource(); !ok || res.Type != "0x1::account::Account" || res.Data["sequence_number"] != "8" {
+		t.Errorf("WriteResource() = %+v, %v", res, ok)
+	}
+	if changes[1].Resource != "0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>" {
+		t.Errorf("delete_resource = %+v", changes[1])
+	}
+	if module, ok := changes[2].WriteModule(); !ok || module.ByteCode != "0xa11ceb0b" || module.ABI.Name != "demo" {
+		t.Errorf("WriteModule() = %+v, %v", module, ok)
+	}
+	if item, ok := changes[3].TableData(); !ok || changes[3].Handle != "0x9" || changes[3].Value != "0x02" || item.ValueType != "u64" {
+		t.Errorf("TableData() = %+v, %v", item, ok)
+	}
+	if item, ok := changes[4].TableData(); ok || changes[4].Key != "0x03" {
+		t.Errorf("TableData() of an undecoded item = %+v, %v", item, ok)
+	}
+
+	if err = json.Unmarshal([]byte(testBlockMetadataTransaction), &tx); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if typed, err = tx.Typed(); err != nil {
+		t.Fatalf("Typed() error = %v", err)
+	}
+	block, ok := typed.BlockMetadata()
+	if !ok || block.Round != 10 || block.Proposer != "0x12" || len(block.PreviousBlockVotesBitvec) != 2 || block.PreviousBlockVotesBitvec[0] != 255 {
+		t.Errorf("BlockMetadata() = %+v, %v", block, ok)
+	}
+	if info, ok := typed.Info(); !ok || info.Version != 1233 {
+		t.Errorf("Info() = %+v, %v", info, ok)
+	}
+
+	if _, err = (&types.Transaction{}).Typed(); !errors.Is(err, types.ErrTxRawNull) {
+		t.Errorf("Typed() error = %v, want %v", err, types.ErrTxRawNull)
+	}
+}
+
+// testTxNode serves the transaction 0xabc by hash from its responses in turn, the last one repeated,
+// null is not found. The ledger is at 2000 seconds
+func testTxNode(responses ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			fmt.Fprint(w, `{"chain_id": 4, "ledger_version": "10", "oldest_ledger_version": "0", "ledger_timestamp": "2000000000"}`)
+			return
+		}
+
+		resp := responses[0]
+		if len(responses) > 1 {
+			responses = responses[1:]
+		}
+		if resp == "null" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Transaction not found by Transaction hash(0xabc)", "error_code": "transaction_not_found"}`)
+			return
+		}
+		fmt.Fprint(w, resp)
+	}))
+}
+
+func TestAptClient_WaitForTransaction(t *testing.T) {
+	pending := func(expiration int) string {
+		return fmt.Sprintf(`{"type": "pending_transaction", "hash": "0xabc", "expiration_timestamp_secs": "%d"}`, expiration)
+	}
+
+	tests := []struct {
+		name       string
+		responses  []string
+		expiration uint64
+		wantErr    error
+	}{
+		{name: "committed", responses: []string{pending(3000), testUserTransaction}},
+		{name: "expired after pending", responses: []string{pending(1000), "null"}, wantErr: types.ErrTxExpired},
+		{name: "expired before seen", responses: []string{"null"}, expiration: 1000, wantErr: types.ErrTxExpired},
+		{name: "committed before the ledger read", responses: []string{"null", testUserTransaction}, expiration: 1000},
+		{name: "not expired yet", responses: []string{"null", testUserTransaction}, expiration: 3000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := testTxNode(tt.responses...)
+			defer server.Close()
+
+			c, err := NewAptClient(server.URL)
+			if err != nil {
+				t.Fatalf("NewAptClient() error = %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			tx, err := c.waitForTransaction(ctx, "0xabc", tt.expiration)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("waitForTransaction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && tx.Version != "1234" {
+				t.Errorf("waitForTransaction() = %+v", tx)
+			}
+		})
+	}
+}
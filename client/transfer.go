@@ -0,0 +1,250 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+const (
+	AptosAccountTransfer           = "0x1::aptos_account::transfer"
+	AptosAccountTransferCoins      = "0x1::aptos_account::transfer_coins"
+	AptosAccountBatchTransfer      = "0x1::aptos_account::batch_transfer"
+	AptosAccountBatchTransferCoins = "0x1::aptos_account::batch_transfer_coins"
+)
+
+// TransferAPTPayload transfers APT by aptos_account::transfer, it creates recipient when absent
+func TransferAPTPayload(recipient string, amount *types.Amount) (*types.EntryFunctionPayload, error) {
+	return transferPayload(AptosAccountTransfer, nil, recipient, amount, types.AptDecimals)
+}
+
+// TransferCoinsPayload transfers coinType of decimals by aptos_account::transfer_coins, it creates recipient
+// and registers its CoinStore when absent. amount is rescaled to decimals
+func TransferCoinsPayload(coinType, recipient string, amount *types.Amount, decimals uint8) (*types.EntryFunctionPayload, error) {
+	if coinType == "" {
+		return nil, types.ErrCoinTypeNull
+	}
+	return transferPayload(AptosAccountTransferCoins, []string{coinType}, recipient, amount, decimals)
+}
+
+func transferPayload(function string, typeArguments []string, recipient string, amount *types.Amount, decimals uint8) (*types.EntryFunctionPayload, error) {
+	recipient, err := checkAccount(recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := amount.BaseUnits(decimals)
+	if err != nil {
+		return nil, err
+	}
+
+	if typeArguments == nil {
+		typeArguments = []string{}
+	}
+
+	return &types.EntryFunctionPayload{
+		Type:          types.EntryFunctionPayloadTy,
+		Function:      function,
+		TypeArguments: typeArguments,
+		Arguments:     []interface{}{recipient, strconv.FormatUint(value, 10)},
+	}, nil
+}
+
+// BatchTransferPayload pays amounts[i] to recipients[i] in one transaction, by aptos_account::batch_transfer
+// for APT or an empty coinType and by aptos_account::batch_transfer_coins for other coins. Amounts are
+// rescaled to decimals, the decimals of coinType, APT always has types.AptDecimals
+func BatchTransferPayload(coinType string, recipients []string, amounts []*types.Amount, decimals uint8) (*types.EntryFunctionPayload, error) {
+	if len(recipients) == 0 || len(recipients) != len(amounts) {
+		return nil, types.ErrBatchTransferLen
+	}
+
+	if coinType == "" || coinType == types.AptCoinTy {
+		decimals = types.AptDecimals
+	}
+
+	addresses := make([]interface{}, 0, len(recipients))
+	values := make([]interface{}, 0, len(amounts))
+	for i, recipient := range recipients {
+		address, err := checkAccount(recipient)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := amounts[i].BaseUnits(decimals)
+		if err != nil {
+			return nil, err
+		}
+
+		addresses = append(addresses, address)
+		values = append(values, strconv.FormatUint(value, 10))
+	}
+
+	payload := &types.EntryFunctionPayload{
+		Type:          types.EntryFunctionPayloadTy,
+		Function:      AptosAccountBatchTransfer,
+		TypeArguments: []string{},
+		Arguments:     []interface{}{addresses, values},
+	}
+
+	if coinType != "" && coinType != types.AptCoinTy {
+		payload.Function = AptosAccountBatchTransferCoins
+		payload.TypeArguments = []string{coinType}
+	}
+	return payload, nil
+}
+
+// CanReceiveCoin reports whether aptos_account transfers of coinType to recipient succeed: recipient does not
+// exist yet, already has a CoinStore<coinType> or a primary store of the fungible asset paired with coinType,
+// or accepts arbitrary coins by its DirectTransferConfig
+func (a *AptClient) CanReceiveCoin(recipient types.AccountAddress, coinType string) (bool, error) {
+	if coinType == "" {
+		return false, types.ErrCoinTypeNull
+	}
+
+	_, err := a.AccountResourceByType(recipient, types.AptAccountTy, "")
+	if errors.Is(err, types.ErrResourceNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	_, err = a.AccountResourceByType(recipient, CoinStoreType(coinType), "")
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, types.ErrResourceNotFound) {
+		return false, err
+	}
+
+	ok, err := a.hasPairedStore(recipient, coinType)
+	if err != nil || ok {
+		return ok, err
+	}
+
+	res, err := a.AccountResourceByType(recipient, types.DirectTransferConfigTy, "")
+	if errors.Is(err, types.ErrResourceNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var config types.DirectTransferConfig
+	if err = decodeResource(res, &config); err != nil {
+		return false, err
+	}
+	return config.AllowArbitraryCoinTransfers, nil
+}
+
+// TransferAPT sends amount octas of APT from account to recipient, aptos_account::transfer creates recipient
+// and its APT store when absent so recipient is not checked
+func (a *AptClient) TransferAPT(account *types.AptAccount, recipient types.AccountAddress, amount *types.Amount, opts *TxOptions) (*types.Transaction, error) {
+	payload, err := TransferAPTPayload(recipient.String(), amount)
+	if err != nil {
+		return nil, err
+	}
+	return a.SubmitPayload(account, payload, opts)
+}
+
+// TransferCoins sends amount of coinType from account to recipient after the CanReceiveCoin check,
+// amount is rescaled to the decimals of the CoinInfo of coinType
+func (a *AptClient) TransferCoins(account *types.AptAccount, coinType string, recipient types.AccountAddress, amount *types.Amount, opts *TxOptions) (*types.Transaction, error) {
+	decimals, err := a.coinDecimals(coinType)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := TransferCoinsPayload(coinType, recipient.String(), amount, decimals)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = a.checkReceiver(recipient, coinType); err != nil {
+		return nil, err
+	}
+	return a.SubmitPayload(account, payload, opts)
+}
+
+// BatchTransfer sends amounts[i] of coinType to recipients[i] in one transaction after checking every recipient,
+// APT recipients are not checked like TransferAPT
+func (a *AptClient) BatchTransfer(account *types.AptAccount, coinType string, recipients []types.AccountAddress, amounts []*types.Amount, opts *TxOptions) (*types.Transaction, error) {
+	if coinType == "" {
+		coinType = types.AptCoinTy
+	}
+
+	decimals, err := a.coinDecimals(coinType)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		addresses = append(addresses, recipient.String())
+	}
+
+	payload, err := BatchTransferPayload(coinType, addresses, amounts, decimals)
+	if err != nil {
+		return nil, err
+	}
+
+	if coinType != types.AptCoinTy {
+		for _, recipient := range recipients {
+			if err = a.checkReceiver(recipient, coinType); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return a.SubmitPayload(account, payload, opts)
+}
+
+// hasPairedStore reports whether owner has a primary store of the fungible asset paired with coinType,
+// coins migrated to fungible assets are deposited there
+func (a *AptClient) hasPairedStore(owner types.AccountAddress, coinType string) (bool, error) {
+	metadata, err := a.PairedMetadata(coinType)
+	if errors.Is(err, types.ErrTableItemNotFound) || errors.Is(err, types.ErrResourceNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	store, err := PrimaryStoreAddress(owner.String(), metadata)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = a.accountResource(context.Background(), store, types.FungibleStoreTy, "")
+	if errors.Is(err, types.ErrResourceNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// coinDecimals returns the decimals of coinType, from its CoinInfo unless it is APT
+func (a *AptClient) coinDecimals(coinType string) (uint8, error) {
+	if coinType == types.AptCoinTy {
+		return types.AptDecimals, nil
+	}
+
+	info, err := a.CoinInfo(coinType)
+	if err != nil {
+		return 0, err
+	}
+	return info.Decimals, nil
+}
+
+func (a *AptClient) checkReceiver(recipient types.AccountAddress, coinType string) error {
+	ok, err := a.CanReceiveCoin(recipient, coinType)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: %s for %s", types.ErrCoinNotAccepted, recipient, coinType)
+	}
+	return nil
+}
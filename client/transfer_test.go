@@ -0,0 +1,242 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+func TestTransferPayload(t *testing.T) {
+	recipient := "0x5792c985bc96f436270bd2a3c692210b09c7febb8889345ceefdbae4bacfe498"
+	amount := types.NewAmount(big.NewInt(150000000), 8)
+
+	tests := []struct {
+		name    string
+		payload func() (*types.EntryFunctionPayload, error)
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "transfer apt",
+			payload: func() (*types.EntryFunctionPayload, error) { return TransferAPTPayload(recipient, amount) },
+			want:    `{"type":"entry_function_payload","function":"0x1::aptos_account::transfer","type_arguments":[],"arguments":["` + recipient + `","150000000"]}`,
+		},
+		{
+			name: "transfer coins",
+			payload: func() (*types.EntryFunctionPayload, error) {
+				return TransferCoinsPayload("0x1::test::Coin", recipient, amount, 8)
+			},
+			want: `{"type":"entry_function_payload","function":"0x1::aptos_account::transfer_coins","type_arguments":["0x1::test::Coin"],"arguments":["` + recipient + `","150000000"]}`,
+		},
+		{
+			name: "batch transfer apt",
+			payload: func() (*types.EntryFunctionPayload, error) {
+				return BatchTransferPayload("", []string{recipient, "0x1"}, []*types.Amount{amount, amount}, 0)
+			},
+			want: `{"type":"entry_function_payload","function":"0x1::aptos_account::batch_transfer","type_arguments":[],"arguments":[["` + recipient + `","0x1"],["150000000","150000000"]]}`,
+		},
+		{
+			name: "batch transfer coins",
+			payload: func() (*types.EntryFunctionPayload, error) {
+				return BatchTransferPayload("0x1::test::Coin", []string{recipient}, []*types.Amount{amount}, 8)
+			},
+			want: `{"type":"entry_function_payload","function":"0x1::aptos_account::batch_transfer_coins","type_arguments":["0x1::test::Coin"],"arguments":[["` + recipient + `"],["150000000"]]}`,
+		},
+		{
+			name: "batch length mismatched",
+			payload: func() (*types.EntryFunctionPayload, error) {
+				return BatchTransferPayload("", []string{recipient}, nil, 8)
+			},
+			wantErr: types.ErrBatchTransferLen,
+		},
+		{
+			name: "amount above u64",
+			payload: func() (*types.EntryFunctionPayload, error) {
+				return TransferAPTPayload(recipient, types.NewAmount(new(big.Int).Lsh(big.NewInt(1), 64), 8))
+			},
+			wantErr: types.ErrAmountOverflow,
+		},
+		{
+			name: "amount of 6 decimals to apt",
+			payload: func() (*types.EntryFunctionPayload, error) {
+				return TransferAPTPayload(recipient, types.NewAmount(big.NewInt(1500000), 6))
+			},
+			want: `{"type":"entry_function_payload","function":"0x1::aptos_account::transfer","type_arguments":[],"arguments":["` + recipient + `","150000000"]}`,
+		},
+		{
+			name: "amount of 8 decimals to a coin of 6",
+			payload: func() (*types.EntryFunctionPayload, error) {
+				return TransferCoinsPayload("0x1::test::Coin", recipient, amount, 6)
+			},
+			want: `{"type":"entry_function_payload","function":"0x1::aptos_account::transfer_coins","type_arguments":["0x1::test::Coin"],"arguments":["` + recipient + `","1500000"]}`,
+		},
+		{
+			name: "amount finer than the coin",
+			payload: func() (*types.EntryFunctionPayload, error) {
+				return BatchTransferPayload("0x1::test::Coin", []string{recipient}, []*types.Amount{types.NewAmount(big.NewInt(1), 8)}, 6)
+			},
+			wantErr: types.ErrAmountPrecision,
+		},
+		{
+			name: "nil amount",
+			payload: func() (*types.EntryFunctionPayload, error) {
+				return TransferAPTPayload(recipient, nil)
+			},
+			wantErr: types.ErrAmountNull,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := tt.payload()
+			if err != tt.wantErr {
+				t.Errorf("payload error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			b, _ := json.Marshal(payload)
+			if string(b) != tt.want {
+				t.Errorf("payload = %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
+
+// testCoinNode is a node where 0xbeef::m::C is paired with the fungible asset 0xfa and 0xbeef::m::D is not
+func testCoinNode(t *testing.T, resources map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/item") {
+			var item struct {
+				Key struct {
+					StructName string `json:"struct_name"`
+				} `json:"key"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+				t.Errorf("decode table item error: %s", err)
+			}
+			if item.Key.StructName == "0x43" {
+				fmt.Fprint(w, `"0xfa"`)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "table item not found", "error_code": "table_item_not_found"}`)
+			return
+		}
+
+		data, ok := resources[strings.TrimPrefix(r.URL.Path, "/accounts/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "resource not found", "error_code": "resource_not_found"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"type": "%s", "data": %s}`, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], data)
+	}))
+}
+
+func TestAptClient_CanReceiveCoin(t *testing.T) {
+	faOnly, _ := checkAccount("0xcafe")
+	noStore, _ := checkAccount("0xd00d")
+	store, _ := PrimaryStoreAddress(faOnly, "0xfa")
+	store, _ = checkAccount(store)
+
+	closed := `{"allow_arbitrary_coin_transfers": false}`
+	server := testCoinNode(t, map[string]string{
+		"0x1/resource/" + types.CoinConversionMapTy:           `{"coin_to_fungible_asset_map": {"handle": "0x99"}}`,
+		faOnly + "/resource/" + types.AptAccountTy:            `{}`,
+		faOnly + "/resource/" + types.DirectTransferConfigTy:  closed,
+		store + "/resource/" + types.FungibleStoreTy:          `{"balance": "1"}`,
+		noStore + "/resource/" + types.AptAccountTy:           `{}`,
+		noStore + "/resource/" + types.DirectTransferConfigTy: closed,
+	})
+	defer server.Close()
+
+	c, err := NewAptClient(server.URL)
+	if err != nil {
+		t.Fatalf("NewAptClient() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		recipient string
+		coinType  string
+		want      bool
+	}{
+		{"fa only account of a paired coin", faOnly, "0xbeef::m::C", true},
+		{"fa only account of an unpaired coin", faOnly, "0xbeef::m::D", false},
+		{"no store of the paired coin", noStore, "0xbeef::m::C", false},
+		{"absent account", "0xf00d", "0xbeef::m::D", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.CanReceiveCoin(types.MustParseAccountAddress(tt.recipient), tt.coinType)
+			if err != nil || got != tt.want {
+				t.Errorf("CanReceiveCoin() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAptClient_TransferAPT(t *testing.T) {
+	tests := []struct {
+		name        string
+		rpc         string
+		mnemonic    string
+		receiptAddr string
+		amount      string
+	}{
+		{
+			name:        "transfer apt",
+			rpc:         RPC_ADDR,
+			mnemonic:    os.Getenv("KEY"),
+			receiptAddr: "0x6d829df49edf618de9002d16b03118f50cb0b22cb56901349720a07f6a5b10c5",
+			amount:      "0.000001",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewAptClient(tt.rpc)
+			if err != nil {
+				t.Logf("new apt client error: %s", err)
+				return
+			}
+
+			ok, err := c.CanReceiveCoin(types.MustParseAccountAddress(tt.receiptAddr), types.AptCoinTy)
+			if err != nil {
+				t.Logf("can receive coin error: %s", err)
+				return
+			}
+			t.Logf("%s can receive apt: %v", tt.receiptAddr, ok)
+
+			account, err := NewAptAccount(tt.mnemonic, "").AccountFromMnemonic(0)
+			if err != nil {
+				t.Logf("account from mnemonic error: %s", err)
+				return
+			}
+
+			amount, err := types.ParseAmount(tt.amount, 8)
+			if err != nil {
+				t.Errorf("parse amount error: %s", err)
+				return
+			}
+
+			tx, err := c.TransferAPT(account, types.MustParseAccountAddress(tt.receiptAddr), amount, nil)
+			if err != nil {
+				t.Logf("transfer apt error: %s", err)
+				return
+			}
+			t.Logf("transfer apt hash: %s", tx.Hash)
+		})
+	}
+}
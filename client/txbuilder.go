@@ -0,0 +1,228 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/threeandtwo/aptclient/bcs"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+const (
+	DefaultMaxGasAmount   uint64 = 200000
+	DefaultTxExpiration          = 10 * time.Minute
+	DefaultTxPollInterval        = time.Second
+
+	SignedTxBCSContentType = "application/x.aptos.signed_transaction+bcs"
+)
+
+// TxOptions overrides the fields BuildTransaction fills from the chain, zero values keep the defaults
+type TxOptions struct {
+	// SequenceNumber is read by GetNonce when nil
+	SequenceNumber *uint64
+	// GasUnitPrice is the EstimateGasPrice when 0
+	GasUnitPrice uint64
+	// MaxGasAmount is DefaultMaxGasAmount when 0
+	MaxGasAmount uint64
+	// Expiration is DefaultTxExpiration from now when 0
+	Expiration time.Duration
+}
+
+// BuildTransaction returns the unsigned transaction of payload sent by sender
+func (a *AptClient) BuildTransaction(sender types.AccountAddress, payload interface{}, opts *TxOptions) (*types.UnsignedTx, error) {
+	if payload == nil {
+		return nil, types.ErrPayloadNull
+	}
+
+	var err error
+	if opts == nil {
+		opts = &TxOptions{}
+	}
+
+	var nonce uint64
+	if opts.SequenceNumber != nil {
+		nonce = *opts.SequenceNumber
+	} else if nonce, err = a.GetNonce(sender); err != nil {
+		return nil, err
+	}
+
+	gasUnitPrice := opts.GasUnitPrice
+	if gasUnitPrice == 0 {
+		if gasUnitPrice, err = a.EstimateGasPrice(); err != nil {
+			return nil, err
+		}
+	}
+
+	maxGasAmount := opts.MaxGasAmount
+	if maxGasAmount == 0 {
+		maxGasAmount = DefaultMaxGasAmount
+	}
+
+	expiration := opts.Expiration
+	if expiration == 0 {
+		expiration = DefaultTxExpiration
+	}
+
+	return &types.UnsignedTx{
+		Sender:         sender.String(),
+		SequenceNumber: nonce,
+		MaxGasAmount:   maxGasAmount,
+		GasUnitPrice:   gasUnitPrice,
+		ExpirationTime: uint64(time.Now().Add(expiration).Unix()),
+		Payload:        payload,
+	}, nil
+}
+
+// SignAndSubmit signs unsignedTx by account and submits it, the returned transaction is pending
+func (a *AptClient) SignAndSubmit(account *types.AptAccount, unsignedTx *types.UnsignedTx) (*types.Transaction, error) {
+	signedTx, err := a.SignTransaction(account, unsignedTx)
+	if err != nil {
+		return nil, err
+	}
+	return a.SubmitTx(signedTx)
+}
+
+// SubmitPayload builds, signs and submits payload sent by account
+func (a *AptClient) SubmitPayload(account *types.AptAccount, payload interface{}, opts *TxOptions) (*types.Transaction, error) {
+	sender, err := types.ParseAccountAddress(account.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	unsignedTx, err := a.BuildTransaction(sender, payload, opts)
+	if err != nil {
+		return nil, err
+	}
+	return a.SignAndSubmit(account, unsignedTx)
+}
+
+// ChainId reads the chain id of the node from LedgerInfo
+func (a *AptClient) ChainId() (uint8, error) {
+	info, err := a.LedgerInfo()
+	if err != nil {
+		return 0, err
+	}
+	return uint8(info.ChainID), nil
+}
+
+// SignRawTransaction signs raw by account locally, the signing message is raw.SigningMessage instead
+// of the one of encode_submission
+func SignRawTransaction(account *types.AptAccount, raw *types.RawTransaction) (*types.SignedTransaction, error) {
+	msg, err := raw.SigningMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := txSignature(account, msg)
+	if err != nil {
+		return nil, err
+	}
+	return &types.SignedTransaction{Raw: raw, Signature: sig}, nil
+}
+
+// SubmitBCSTx submits signedTx as application/x.aptos.signed_transaction+bcs, the returned transaction is pending
+func (a *AptClient) SubmitBCSTx(ctx context.Context, signedTx *types.SignedTransaction) (*types.Transaction, error) {
+	body, err := bcs.Serialize(signedTx)
+	if err != nil {
+		return nil, err
+	}
+
+	rpc := fmt.Sprintf("%s/transactions", a.rpc)
+	header := map[string]string{"content-type": SignedTxBCSContentType, "accept": "application/json"}
+	req, err := NewNet(rpc, header, nil).WithBody(body).WithContext(ctx).Request(PostTy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = respErr(req); err != nil {
+		return nil, err
+	}
+
+	var tx *types.Transaction
+	err = json.Unmarshal([]byte(req), &tx)
+	return tx, err
+}
+
+// SubmitPayloadBCS builds payload sent by account, a *types.Script or *types.EntryFunction, signs it
+// locally and submits it in BCS, script arguments of any Move type are sent this way
+func (a *AptClient) SubmitPayloadBCS(ctx context.Context, account *types.AptAccount, payload bcs.Marshaler, opts *TxOptions) (*types.Transaction, error) {
+	sender, err := types.ParseAccountAddress(account.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	unsignedTx, err := a.BuildTransaction(sender, payload, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	chainId, err := a.ChainId()
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := types.NewRawTransaction(unsignedTx, chainId)
+	if err != nil {
+		return nil, err
+	}
+
+	signedTx, err := SignRawTransaction(account, raw)
+	if err != nil {
+		return nil, err
+	}
+	return a.SubmitBCSTx(ctx, signedTx)
+}
+
+// WaitForTransaction polls hash every DefaultTxPollInterval until it is committed, transactions the VM
+// aborted are returned with types.ErrTxFailed wrapping their vm_status. Once the transaction was seen
+// pending it gives up with types.ErrTxExpired when it is gone after its expiration_timestamp_secs
+func (a *AptClient) WaitForTransaction(ctx context.Context, hash string) (*types.Transaction, error) {
+	return a.waitForTransaction(ctx, hash, 0)
+}
+
+// waitForTransaction is WaitForTransaction of a transaction expiring at expiration seconds, 0 when unknown
+func (a *AptClient) waitForTransaction(ctx context.Context, hash string, expiration uint64) (*types.Transaction, error) {
+	ticker := time.NewTicker(DefaultTxPollInterval)
+	defer ticker.Stop()
+
+	expired := false
+	for {
+		tx, err := a.TransactionByHash(hash)
+		switch {
+		case err == nil && tx.Type != types.PendingTransactionTy:
+			if !tx.Success {
+				return tx, fmt.Errorf("%w: %s %s", types.ErrTxFailed, hash, tx.VMStatus)
+			}
+			return tx, nil
+		case err == nil:
+			if secs, err := strconv.ParseUint(tx.ExpirationTimestampSecs, 10, 64); err == nil {
+				expiration = secs
+			}
+		case !errors.Is(err, types.ErrTxNotFound):
+			return nil, err
+		case expired:
+			return nil, fmt.Errorf("%w: %s at %d", types.ErrTxExpired, hash, expiration)
+		case expiration > 0:
+			info, err := a.ledgerInfo(ctx)
+			if err != nil {
+				return nil, err
+			}
+			// the ledger timestamp is in microseconds, the hash is looked up once more past the
+			// expiration in case the transaction was committed between the two requests
+			if info.LedgerTimestamp/1e6 > expiration {
+				expired = true
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+// VanityOptions matches the hex of the address after 0x, Prefix and Suffix are case-insensitive
+// and may be empty but not both
+type VanityOptions struct {
+	Prefix string
+	Suffix string
+
+	// Workers defaults to runtime.NumCPU()
+	Workers int
+
+	// OnProgress is called every ProgressInterval (default 1s) until the search ends
+	OnProgress       func(*VanityProgress)
+	ProgressInterval time.Duration
+}
+
+type VanityProgress struct {
+	Attempts uint64
+	Elapsed  time.Duration
+	// Rate is attempts per second
+	Rate float64
+	// ExpectedAttempts is 16^(len(Prefix)+len(Suffix)), the mean attempts of a match
+	ExpectedAttempts float64
+	// Expected is the mean time left at Rate, the search has no memory so it does not shrink with Attempts
+	Expected time.Duration
+}
+
+type VanityResult struct {
+	Account  *types.AptAccount
+	Attempts uint64
+	Elapsed  time.Duration
+
+	// PrivateKey is the base58 form of PrivateKey2Str, PrivateKeyHex is the 0x seed form,
+	// NewAptAccount loads both
+	PrivateKey    string
+	PrivateKeyHex string
+}
+
+// GenerateVanityAccount searches random ed25519 keys until the address matches opts,
+// it returns ctx.Err() when ctx is done first
+func GenerateVanityAccount(ctx context.Context, opts *VanityOptions) (*VanityResult, error) {
+	prefix, suffix, err := vanityPattern(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	interval := opts.ProgressInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		attempts uint64
+		once     sync.Once
+		found    *types.AptAccount
+		wg       sync.WaitGroup
+		genErr   error
+	)
+
+	start := time.Now()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				account, err := NewAptAccount("", "").genPrvKey()
+				if err != nil {
+					once.Do(func() { genErr = err })
+					cancel()
+					return
+				}
+				atomic.AddUint64(&attempts, 1)
+
+				address := account.Address[2:]
+				if strings.HasPrefix(address, prefix) && strings.HasSuffix(address, suffix) {
+					once.Do(func() { found = account })
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	expectedAttempts := math.Pow(16, float64(len(prefix)+len(suffix)))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for searching := true; searching; {
+		select {
+		case <-ticker.C:
+			if opts.OnProgress != nil {
+				opts.OnProgress(vanityProgress(atomic.LoadUint64(&attempts), time.Since(start), expectedAttempts))
+			}
+		case <-done:
+			searching = false
+		}
+	}
+
+	if genErr != nil {
+		return nil, genErr
+	}
+	if found == nil {
+		return nil, ctx.Err()
+	}
+
+	return &VanityResult{
+		Account:       found,
+		Attempts:      atomic.LoadUint64(&attempts),
+		Elapsed:       time.Since(start),
+		PrivateKey:    PrivateKey2Str(found.PrivateKey),
+		PrivateKeyHex: "0x" + hex.EncodeToString(found.PrivateKey.Seed()),
+	}, nil
+}
+
+func vanityPattern(opts *VanityOptions) (string, string, error) {
+	if opts == nil {
+		return "", "", types.ErrVanityPattern
+	}
+
+	prefix := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(opts.Prefix, "0x"), "0X"))
+	suffix := strings.ToLower(opts.Suffix)
+	if prefix == "" && suffix == "" || len(prefix)+len(suffix) > types.AccountAddressLength*2 {
+		return "", "", types.ErrVanityPattern
+	}
+
+	for _, c := range prefix + suffix {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return "", "", types.ErrVanityPattern
+		}
+	}
+	return prefix, suffix, nil
+}
+
+func vanityProgress(attempts uint64, elapsed time.Duration, expectedAttempts float64) *VanityProgress {
+	progress := &VanityProgress{
+		Attempts:         attempts,
+		Elapsed:          elapsed,
+		ExpectedAttempts: expectedAttempts,
+	}
+
+	if elapsed > 0 {
+		progress.Rate = float64(attempts) / elapsed.Seconds()
+	}
+	if progress.Rate > 0 {
+		expected := expectedAttempts / progress.Rate
+		if expected > float64(math.MaxInt64)/float64(time.Second) {
+			progress.Expected = time.Duration(math.MaxInt64)
+		} else {
+			progress.Expected = time.Duration(expected * float64(time.Second))
+		}
+	}
+	return progress
+}
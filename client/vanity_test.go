@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+func TestGenerateVanityAccount(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    *VanityOptions
+		timeout time.Duration
+		wantErr error
+	}{
+		{
+			name: "prefix",
+			opts: &VanityOptions{Prefix: "0xA"},
+		},
+		{
+			name: "prefix and suffix",
+			opts: &VanityOptions{Prefix: "b", Suffix: "c", Workers: 2},
+		},
+		{
+			name:    "empty",
+			opts:    &VanityOptions{},
+			wantErr: types.ErrVanityPattern,
+		},
+		{
+			name:    "not hex",
+			opts:    &VanityOptions{Suffix: "xyz"},
+			wantErr: types.ErrVanityPattern,
+		},
+		{
+			name:    "canceled",
+			opts:    &VanityOptions{Prefix: strings.Repeat("0", 64)},
+			timeout: 100 * time.Millisecond,
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeout := tt.timeout
+			if timeout == 0 {
+				timeout = time.Minute
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			res, err := GenerateVanityAccount(ctx, tt.opts)
+			if err != tt.wantErr {
+				t.Errorf("GenerateVanityAccount() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			address := res.Account.Address[2:]
+			if !strings.HasPrefix(address, strings.ToLower(strings.TrimPrefix(tt.opts.Prefix, "0x"))) ||
+				!strings.HasSuffix(address, tt.opts.Suffix) {
+				t.Errorf("address %s mismatched", res.Account.Address)
+			}
+
+			for _, key := range []string{res.PrivateKey, res.PrivateKeyHex} {
+				account, err := NewAptAccount(key, "").AccountFromPrivateKey()
+				if err != nil || account.Address != res.Account.Address {
+					t.Errorf("load %s = %v, %v", key, account, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"strings"
+
+	"github.com/threeandtwo/aptclient/hexutil"
+	"github.com/threeandtwo/aptclient/key_manager"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+const (
+	// MultiEd25519 signatures end with a 4 bytes bitmap of the signing keys
+	multiEd25519BitmapLen     = 4
+	maxMultiEd25519PublicKeys = 32
+)
+
+// Ed25519Verifier verifies ed25519 signatures of legacy ed25519 accounts
+type Ed25519Verifier struct {
+	publicKey ed25519.PublicKey
+}
+
+func NewEd25519Verifier(publicKey string) (*Ed25519Verifier, error) {
+	pubKey, err := hexutil.Decode(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pubKey) != ed25519.PublicKeySize {
+		return nil, types.ErrPublicKeyLen
+	}
+	return &Ed25519Verifier{publicKey: pubKey}, nil
+}
+
+func (v *Ed25519Verifier) AuthKey() string {
+	return hexutil.Encode(key_manager.AuthKey(key_manager.Ed25519Scheme, v.publicKey))
+}
+
+func (v *Ed25519Verifier) Verify(msg, sig []byte) bool {
+	return ed25519.Verify(v.publicKey, msg, sig)
+}
+
+// MultiEd25519Verifier verifies K-of-N multi ed25519 signatures, a signature is
+// the ed25519 signatures ordered by key index followed by a 4 bytes bitmap of the signing keys
+type MultiEd25519Verifier struct {
+	publicKeys []ed25519.PublicKey
+	threshold  uint8
+}
+
+func NewMultiEd25519Verifier(publicKeys []string, threshold uint8) (*MultiEd25519Verifier, error) {
+	if len(publicKeys) == 0 || len(publicKeys) > maxMultiEd25519PublicKeys {
+		return nil, types.ErrMultiKeyCount
+	}
+
+	if threshold == 0 || int(threshold) > len(publicKeys) {
+		return nil, types.ErrMultiKeyThreshold
+	}
+
+	v := &MultiEd25519Verifier{threshold: threshold}
+	for _, publicKey := range publicKeys {
+		pubKey, err := hexutil.Decode(publicKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(pubKey) != ed25519.PublicKeySize {
+			return nil, types.ErrPublicKeyLen
+		}
+		v.publicKeys = append(v.publicKeys, pubKey)
+	}
+	return v, nil
+}
+
+// AuthKey returns sha3-256(public_key_1 || ... || public_key_n || threshold || 0x01)
+func (v *MultiEd25519Verifier) AuthKey() string {
+	data := make([][]byte, 0, len(v.publicKeys)+1)
+	for _, pubKey := range v.publicKeys {
+		data = append(data, pubKey)
+	}
+	data = append(data, []byte{v.threshold})
+	return hexutil.Encode(key_manager.AuthKey(key_manager.MultiEd25519Scheme, data...))
+}
+
+func (v *MultiEd25519Verifier) Verify(msg, sig []byte) bool {
+	if len(sig) < multiEd25519BitmapLen || (len(sig)-multiEd25519BitmapLen)%ed25519.SignatureSize != 0 {
+		return false
+	}
+
+	bitmap := sig[len(sig)-multiEd25519BitmapLen:]
+	sigs := sig[:len(sig)-multiEd25519BitmapLen]
+	if len(sigs)/ed25519.SignatureSize < int(v.threshold) {
+		return false
+	}
+
+	for i := 0; i < multiEd25519BitmapLen*8; i++ {
+		if bitmap[i/8]&(0x80>>(i%8)) == 0 {
+			continue
+		}
+
+		if i >= len(v.publicKeys) || len(sigs) == 0 {
+			return false
+		}
+
+		if !ed25519.Verify(v.publicKeys[i], msg, sigs[:ed25519.SignatureSize]) {
+			return false
+		}
+		sigs = sigs[ed25519.SignatureSize:]
+	}
+	return len(sigs) == 0
+}
+
+// Secp256k1Verifier verifies secp256k1 ecdsa signatures of single key accounts
+type Secp256k1Verifier struct {
+	publicKey []byte
+}
+
+// NewSecp256k1Verifier takes the 65 bytes uncompressed public key
+func NewSecp256k1Verifier(publicKey string) (*Secp256k1Verifier, error) {
+	pubKey, err := hexutil.Decode(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pubKey) != 65 {
+		return nil, types.ErrPublicKeyLen
+	}
+	return &Secp256k1Verifier{publicKey: pubKey}, nil
+}
+
+func (v *Secp256k1Verifier) AuthKey() string {
+	return hexutil.Encode(key_manager.Secp256k1AuthKey(v.publicKey))
+}
+
+// Verify verifies a r || s signature over sha3-256(msg)
+func (v *Secp256k1Verifier) Verify(msg, sig []byte) bool {
+	return key_manager.VerifySecp256k1(v.publicKey, msg, sig)
+}
+
+// SingleKeyVerifier verifies signatures of single key accounts by the verifier of their key,
+// its auth key is sha3-256(bcs(AnyPublicKey) || 0x02)
+type SingleKeyVerifier struct {
+	verifier IVerifier
+	authKey  string
+}
+
+func (v *SingleKeyVerifier) AuthKey() string {
+	return v.authKey
+}
+
+func (v *SingleKeyVerifier) Verify(msg, sig []byte) bool {
+	return v.verifier.Verify(msg, sig)
+}
+
+// VerifySignature verifies sig by the public key of the verifier only, see VerifySignatureByAddress
+// to check the key is the one of an account
+func VerifySignature(verifier IVerifier, msg, sig []byte) bool {
+	return verifier.Verify(msg, sig)
+}
+
+// NewVerifier returns the verifier of a single key public key, its AuthKey is the one of a single key
+// account. Legacy ed25519 accounts are verified by NewEd25519Verifier
+func NewVerifier(publicKey *types.AnyPublicKey) (IVerifier, error) {
+	switch publicKey.Type {
+	case types.AnyEd25519:
+		verifier, err := NewEd25519Verifier(publicKey.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		authKey, err := SingleKeyAuthKey(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		return &SingleKeyVerifier{verifier: verifier, authKey: authKey}, nil
+	case types.Secp256k1Ecdsa:
+		return NewSecp256k1Verifier(publicKey.Value)
+	default:
+		return nil, types.ErrUnsupportedPublicKey
+	}
+}
+
+// VerifySignatureByAddress checks the on-chain auth key of address is the one of the verifier key and verifies sig,
+// rotated keys are caught as the auth key is read from the account instead of derived from the address
+func (a *AptClient) VerifySignatureByAddress(address types.AccountAddress, verifier IVerifier, msg, sig []byte) (bool, error) {
+	if err := a.checkAuthKey(address, verifier); err != nil {
+		return false, err
+	}
+	return verifier.Verify(msg, sig), nil
+}
+
+// VerifySignedMessageByAddress verifies an off-chain message was signed by the current key of address
+func (a *AptClient) VerifySignedMessageByAddress(address types.AccountAddress, publicKey *types.AnyPublicKey, resp *types.SignMessageResponse) (bool, error) {
+	if resp.Address != "" {
+		signer, err := types.ParseAccountAddress(resp.Address)
+		if err != nil {
+			return false, err
+		}
+
+		if signer != address {
+			return false, types.ErrAddressMismatched
+		}
+	}
+
+	verifier, err := NewVerifier(publicKey)
+	if err != nil {
+		return false, err
+	}
+
+	if err = a.checkAuthKey(address, verifier); err != nil {
+		return false, err
+	}
+	return VerifySignedMessage(publicKey, resp)
+}
+
+func (a *AptClient) checkAuthKey(address types.AccountAddress, verifier IVerifier) error {
+	account, err := a.Account(address)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(account.AuthKey, verifier.AuthKey()) {
+		return types.ErrAuthKeyMismatched
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/threeandtwo/aptclient/types"
+)
+
+func TestVerifySignature(t *testing.T) {
+	msg := []byte("This is a sample message")
+
+	var edAccounts []*types.AptAccount
+	var publicKeys []string
+	for i := 0; i < 3; i++ {
+		_account, err := NewAptAccount(mnemonic, "").AccountFromMnemonic(i)
+		if err != nil {
+			t.Fatalf("ed25519 account error: %s", err)
+		}
+		edAccounts = append(edAccounts, _account)
+		publicKeys = append(publicKeys, _account.PublicKey)
+	}
+
+	secpAccount := NewSecp256k1Account(mnemonic, "")
+	_secpAccount, err := secpAccount.AccountFromMnemonic(0)
+	if err != nil {
+		t.Fatalf("secp256k1 account error: %s", err)
+	}
+
+	edVerifier, _ := NewEd25519Verifier(edAccounts[0].PublicKey)
+	singleKeyVerifier, err := NewVerifier(AnyPublicKey(edAccounts[0]))
+	if err != nil {
+		t.Fatalf("single key verifier error: %s", err)
+	}
+	singleKeyAuthKey, _ := SingleKeyAuthKey(AnyPublicKey(edAccounts[0]))
+	secpVerifier, _ := NewSecp256k1Verifier(_secpAccount.PublicKey)
+	multiVerifier, err := NewMultiEd25519Verifier(publicKeys, 2)
+	if err != nil {
+		t.Fatalf("multi ed25519 verifier error: %s", err)
+	}
+
+	// keys 0 and 2 sign, bitmap 0b10100000
+	multiSig := append(ed25519.Sign(edAccounts[0].PrivateKey, msg), ed25519.Sign(edAccounts[2].PrivateKey, msg)...)
+	multiSig = append(multiSig, 0xa0, 0x00, 0x00, 0x00)
+
+	tests := []struct {
+		name     string
+		verifier IVerifier
+		authKey  string
+		sig      []byte
+		want     bool
+	}{
+		{
+			name:     "ed25519",
+			verifier: edVerifier,
+			authKey:  edAccounts[0].AuthKey,
+			sig:      ed25519.Sign(edAccounts[0].PrivateKey, msg),
+			want:     true,
+		},
+		{
+			name:     "ed25519 by another key",
+			verifier: edVerifier,
+			authKey:  edAccounts[0].AuthKey,
+			sig:      ed25519.Sign(edAccounts[1].PrivateKey, msg),
+			want:     false,
+		},
+		{
+			name:     "single key ed25519",
+			verifier: singleKeyVerifier,
+			authKey:  singleKeyAuthKey,
+			sig:      ed25519.Sign(edAccounts[0].PrivateKey, msg),
+			want:     true,
+		},
+		{
+			name:     "secp256k1",
+			verifier: secpVerifier,
+			authKey:  _secpAccount.AuthKey,
+			sig:      secpAccount.Sign(msg),
+			want:     true,
+		},
+		{
+			name:     "multi ed25519 2 of 3",
+			verifier: multiVerifier,
+			sig:      multiSig,
+			want:     true,
+		},
+		{
+			name:     "multi ed25519 below threshold",
+			verifier: multiVerifier,
+			sig:      append(ed25519.Sign(edAccounts[0].PrivateKey, msg), 0x80, 0x00, 0x00, 0x00),
+			want:     false,
+		},
+		{
+			name:     "multi ed25519 wrong bitmap",
+			verifier: multiVerifier,
+			sig:      append(append([]byte{}, multiSig[:2*ed25519.SignatureSize]...), 0xc0, 0x00, 0x00, 0x00),
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.authKey != "" && tt.verifier.AuthKey() != tt.authKey {
+				t.Errorf("auth key %s mismatched with %s", tt.verifier.AuthKey(), tt.authKey)
+			}
+
+			if got := VerifySignature(tt.verifier, msg, tt.sig); got != tt.want {
+				t.Errorf("verify signature for %s: %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAptClient_VerifySignatureByAddress(t *testing.T) {
+	tests := []struct {
+		name      string
+		rpc       string
+		address   string
+		publicKey string
+	}{
+		{
+			name:      "key mismatched with the account",
+			rpc:       MAINNET_RPC_ADDR,
+			address:   "0x9a61496f9603d4c48d1ccb6d1aebcffefbd0efd6270e46885744c191ef2bfd59",
+			publicKey: "0xea526ba1710343d953461ff68641f1b7df5f23b9042ffa2d2a798d3adb3f3d6c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewAptClient(tt.rpc)
+			if err != nil {
+				t.Logf("new apt client error: %s", err)
+				return
+			}
+
+			verifier, err := NewEd25519Verifier(tt.publicKey)
+			if err != nil {
+				t.Fatalf("new verifier error: %s", err)
+			}
+
+			ok, err := c.VerifySignatureByAddress(types.MustParseAccountAddress(tt.address), verifier, []byte("msg"), make([]byte, ed25519.SignatureSize))
+			if err != nil {
+				t.Logf("verify signature by address for %s error: %s", tt.name, err)
+				return
+			}
+			t.Logf("verified: %v", ok)
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"github.com/threeandtwo/aptclient/bcs"
+	"github.com/threeandtwo/aptclient/hexutil"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+const ViewBCSContentType = "application/x.aptos.view_function+bcs"
+
+// View calls the #[view] function at ledgerVersion, the latest when empty, and returns its values decoded
+// into generic JSON values, args are converted by MoveArgument
+func (a *AptClient) View(ctx context.Context, function string, typeArgs []string, args []interface{}, ledgerVersion string) ([]interface{}, error) {
+	raw, err := a.ViewRaw(ctx, function, typeArgs, args, ledgerVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(raw))
+	for i := range raw {
+		if err = json.Unmarshal(raw[i], &values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// ViewInto is View decoding the i-th returned value into out[i], see DecodeViewValues
+func (a *AptClient) ViewInto(ctx context.Context, function string, typeArgs []string, args []interface{}, ledgerVersion string, out ...interface{}) error {
+	raw, err := a.ViewRaw(ctx, function, typeArgs, args, ledgerVersion)
+	if err != nil {
+		return err
+	}
+	return DecodeViewValues(raw, out...)
+}
+
+// ViewRaw is View keeping the JSON of every returned value
+func (a *AptClient) ViewRaw(ctx context.Context, function string, typeArgs []string, args []interface{}, ledgerVersion string) ([]json.RawMessage, error) {
+	if _, _, err := types.ParseFunctionId(function); err != nil {
+		return nil, err
+	}
+
+	arguments := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		v, err := MoveArgument(arg)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, v)
+	}
+
+	if typeArgs == nil {
+		typeArgs = []string{}
+	}
+
+	params := map[string]interface{}{
+		"function":       function,
+		"type_arguments": typeArgs,
+		"arguments":      arguments,
+	}
+	return a.view(ctx, a.connClient(a.viewRpc(ledgerVersion), params))
+}
+
+// ViewBCS calls function with the BCS request body, args are the BCS bytes of every argument,
+// the values are returned as JSON like ViewRaw
+func (a *AptClient) ViewBCS(ctx context.Context, function string, typeArgs []string, args [][]byte, ledgerVersion string) ([]json.RawMessage, error) {
+	entryFunction, err := types.NewEntryFunction(function, typeArgs, args)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := bcs.Serialize(entryFunction)
+	if err != nil {
+		return nil, err
+	}
+
+	header := map[string]string{"content-type": ViewBCSContentType, "accept": "application/json"}
+	return a.view(ctx, NewNet(a.viewRpc(ledgerVersion), header, nil).WithBody(body))
+}
+
+func (a *AptClient) viewRpc(ledgerVersion string) string {
+	if ledgerVersion == "" {
+		return fmt.Sprintf("%s/view", a.rpc)
+	}
+	return fmt.Sprintf("%s/view?ledger_version=%s", a.rpc, ledgerVersion)
+}
+
+func (a *AptClient) view(ctx context.Context, net *Net) ([]json.RawMessage, error) {
+	req, err := net.WithContext(ctx).Request(PostTy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = respErr(req); err != nil {
+		return nil, err
+	}
+
+	var values []json.RawMessage
+	err = json.Unmarshal([]byte(req), &values)
+	return values, err
+}
+
+// DecodeViewValues decodes values[i] into the pointer out[i], u64 and wider values are JSON strings
+// and decode into *big.Int, *uint64 or *string, others decode by encoding/json
+func DecodeViewValues(values []json.RawMessage, out ...interface{}) error {
+	if len(out) > len(values) {
+		return types.ErrViewValues
+	}
+
+	for i, o := range out {
+		if err := decodeMoveValue(values[i], o); err != nil {
+			return fmt.Errorf("view value %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func decodeMoveValue(raw json.RawMessage, out interface{}) error {
+	switch o := out.(type) {
+	case *big.Int:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return err
+		}
+		v, err := parseU256(s)
+		if err != nil {
+			return err
+		}
+		o.Set(v)
+		return nil
+	case *uint64:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return err
+		}
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		*o = v
+		return nil
+	default:
+		return json.Unmarshal(raw, out)
+	}
+}
+
+// MoveArgument converts v into the JSON the REST API reads for a Move argument: u8, u16 and u32 are numbers,
+// wider integers are decimal strings, []byte is a 0x hex vector<u8>, addresses are strings, maps are
+// structs kept as is and other slices are vectors of their converted elements
+func MoveArgument(v interface{}) (interface{}, error) {
+	switch arg := v.(type) {
+	case nil:
+		return nil, types.ErrMoveArgument
+	case string, bool, uint8, uint16, uint32, json.RawMessage, map[string]interface{}:
+		return arg, nil
+	case uint64:
+		return strconv.FormatUint(arg, 10), nil
+	case uint:
+		return strconv.FormatUint(uint64(arg), 10), nil
+	case int:
+		if arg < 0 {
+			return nil, types.ErrMoveArgument
+		}
+		return strconv.Itoa(arg), nil
+	case int64:
+		if arg < 0 {
+			return nil, types.ErrMoveArgument
+		}
+		return strconv.FormatInt(arg, 10), nil
+	case *big.Int:
+		if arg == nil || arg.Sign() < 0 {
+			return nil, types.ErrMoveArgument
+		}
+		return arg.String(), nil
+	case *types.Amount:
+		if arg == nil || arg.Value == nil {
+			return nil, types.ErrAmountNull
+		}
+		if arg.Value.Sign() < 0 {
+			return nil, types.ErrMoveArgument
+		}
+		return arg.Value.String(), nil
+	case types.AccountAddress:
+		return arg.String(), nil
+	case *types.AccountAddress:
+		return arg.String(), nil
+	case []byte:
+		return hexutil.Encode(arg), nil
+	case types.HexBytes:
+		return hexutil.Encode(arg), nil
+	case types.U64:
+		return strconv.FormatUint(uint64(arg), 10), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%w: %T", types.ErrMoveArgument, v)
+	}
+
+	values := make([]interface{}, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		elem, err := MoveArgument(rv.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, elem)
+	}
+	return values, nil
+}
+
+func parseU256(v string) (*big.Int, error) {
+	u, ok := new(big.Int).SetString(v, 10)
+	if !ok || u.Sign() < 0 || u.BitLen() > 256 {
+		return nil, types.ErrParsedValue
+	}
+	return u, nil
+}
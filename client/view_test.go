@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/threeandtwo/aptclient/bcs"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+func TestParseTypeTag(t *testing.T) {
+	one := strings.Repeat("00", 31) + "01"
+
+	tests := []struct {
+		name    string
+		typeTag string
+		want    string
+		wantBcs string
+		wantErr error
+	}{
+		{name: "u64", typeTag: "u64", want: "u64", wantBcs: "02"},
+		{name: "vector<u8>", typeTag: "vector<u8>", want: "vector<u8>", wantBcs: "0601"},
+		{
+			name:    "struct",
+			typeTag: "0x1::aptos_coin::AptosCoin",
+			want:    "0x1::aptos_coin::AptosCoin",
+			wantBcs: "07" + one + "0a" + hex.EncodeToString([]byte("aptos_coin")) + "09" + hex.EncodeToString([]byte("AptosCoin")) + "00",
+		},
+		{
+			name:    "nested generic with spaces",
+			typeTag: "0x1::coin::CoinStore< 0x01::lp::LP<u8,vector<address>> >",
+			want:    "0x1::coin::CoinStore<0x1::lp::LP<u8, vector<address>>>",
+		},
+		{name: "unknown primitive", typeTag: "u7", wantErr: types.ErrTypeTag},
+		{name: "unclosed", typeTag: "vector<u8", wantErr: types.ErrTypeTag},
+		{name: "trailing", typeTag: "u8>", wantErr: types.ErrTypeTag},
+		{name: "empty argument", typeTag: "0x1::a::B<u8,>", wantErr: types.ErrTypeTag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag, err := types.ParseTypeTag(tt.typeTag)
+			if err != tt.wantErr {
+				t.Errorf("ParseTypeTag() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if tag.String() != tt.want {
+				t.Errorf("String() = %s, want %s", tag.String(), tt.want)
+			}
+
+			b, err := bcs.Serialize(tag)
+			if err != nil {
+				t.Errorf("bcs.Serialize() error = %v", err)
+				return
+			}
+			if tt.wantBcs != "" && hex.EncodeToString(b) != tt.wantBcs {
+				t.Errorf("bcs = %x, want %s", b, tt.wantBcs)
+			}
+
+			var decoded types.TypeTag
+			if err = bcs.Deserialize(&decoded, b); err != nil || decoded.String() != tt.want {
+				t.Errorf("bcs round trip = %s, %v", decoded.String(), err)
+			}
+		})
+	}
+}
+
+func TestMoveArgument(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "u8", arg: uint8(7), want: `7`},
+		{name: "u64", arg: uint64(18446744073709551615), want: `"18446744073709551615"`},
+		{name: "u128", arg: new(big.Int).Lsh(big.NewInt(1), 100), want: `"1267650600228229401496703205376"`},
+		{name: "bytes", arg: []byte("hi"), want: `"0x6869"`},
+		{name: "address", arg: types.AccountOne, want: `"0x1"`},
+		{name: "vector<u64>", arg: []uint64{1, 2}, want: `["1","2"]`},
+		{name: "vector<vector<u8>>", arg: [][]byte{{1}, {2}}, want: `["0x01","0x02"]`},
+		{name: "amount", arg: types.NewAmount(big.NewInt(150000000), 8), want: `"150000000"`},
+		{name: "nil amount", arg: (*types.Amount)(nil), wantErr: true},
+		{name: "nil amount value", arg: &types.Amount{}, wantErr: true},
+		{name: "negative amount", arg: types.NewAmount(big.NewInt(-5), 8), wantErr: true},
+		{name: "negative", arg: -1, wantErr: true},
+		{name: "float", arg: 1.5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MoveArgument(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MoveArgument() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			b, _ := json.Marshal(got)
+			if string(b) != tt.want {
+				t.Errorf("MoveArgument() = %s, want %s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeViewValues(t *testing.T) {
+	var values []json.RawMessage
+	if err := json.Unmarshal([]byte(`["340282366920938463463374607431768211455","42",{"vec":[]},true]`), &values); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	var (
+		u128   big.Int
+		u64    uint64
+		option struct {
+			Vec []string `json:"vec"`
+		}
+		b bool
+	)
+	if err := DecodeViewValues(values, &u128, &u64, &option, &b); err != nil {
+		t.Fatalf("DecodeViewValues() error = %v", err)
+	}
+	if u128.String() != "340282366920938463463374607431768211455" || u64 != 42 || len(option.Vec) != 0 || !b {
+		t.Errorf("DecodeViewValues() = %s %d %v %v", u128.String(), u64, option, b)
+	}
+
+	if err := DecodeViewValues(values[:1], &u128, &u64); err != types.ErrViewValues {
+		t.Errorf("DecodeViewValues() error = %v, want %v", err, types.ErrViewValues)
+	}
+}
+
+func TestAptClient_View(t *testing.T) {
+	c, err := NewAptClient(MAINNET_RPC_ADDR)
+	if err != nil {
+		t.Logf("new apt client error: %s", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var balance uint64
+	err = c.ViewInto(ctx, "0x1::coin::balance", []string{types.AptCoinTy}, []interface{}{types.AccountOne}, "", &balance)
+	if err != nil {
+		t.Logf("view error: %s", err)
+		return
+	}
+	t.Logf("balance of 0x1: %d", balance)
+
+	values, err := c.ViewBCS(ctx, "0x1::coin::balance", []string{types.AptCoinTy}, [][]byte{types.AccountOne.Bytes()}, "")
+	if err != nil {
+		t.Logf("view bcs error: %s", err)
+		return
+	}
+	t.Logf("view bcs values: %s", values)
+}
@@ -0,0 +1,109 @@
+// Command aptgen generates typed Go bindings of a Move module, from a node, a local ABI file or
+// a compiled module.
+//
+//	aptgen -rpc https://fullnode.mainnet.aptoslabs.com/v1 -address 0x1 -module aptos_account -out aptos_account.go
+//	aptgen -abi module.json -pkg mymodule -out mymodule.go
+//	aptgen -abi build/mymodule/bytecode_modules/mymodule.mv -out mymodule.go
+//
+// The ABI file is the JSON of GET /accounts/{address}/module/{module}, or only its abi field, or
+// the .mv bytecode the Move compiler writes, whose ABI is derived by bytecode.Parse.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/threeandtwo/aptclient/bind"
+	"github.com/threeandtwo/aptclient/bytecode"
+	"github.com/threeandtwo/aptclient/client"
+	"github.com/threeandtwo/aptclient/types"
+)
+
+func main() {
+	var (
+		rpc     = flag.String("rpc", "", "node REST API to read the module from")
+		address = flag.String("address", "", "address of the module")
+		module  = flag.String("module", "", "name of the module")
+		abiFile = flag.String("abi", "", "local module JSON, ABI JSON or compiled .mv file, instead of -rpc")
+		pkg     = flag.String("pkg", "", "package of the bindings, the module name by default")
+		out     = flag.String("out", "", "output file, stdout by default")
+	)
+	flag.Parse()
+
+	if err := run(*rpc, *address, *module, *abiFile, *pkg, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "aptgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(rpc, address, module, abiFile, pkg, out string) error {
+	abi, err := loadABI(rpc, address, module, abiFile)
+	if err != nil {
+		return err
+	}
+
+	code, err := bind.Generate(abi, pkg)
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	return os.WriteFile(out, code, 0o644)
+}
+
+func loadABI(rpc, address, module, abiFile string) (*types.MoveModuleABI, error) {
+	if abiFile != "" {
+		b, err := os.ReadFile(abiFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if json.Valid(b) {
+			return decodeABI(b)
+		}
+
+		m, err := bytecode.Parse(b)
+		if err != nil {
+			return nil, err
+		}
+		return m.ABI(), nil
+	}
+
+	if rpc == "" || address == "" || module == "" {
+		return nil, fmt.Errorf("-abi or -rpc, -address and -module are required")
+	}
+
+	moduleAddress, err := types.ParseAccountAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := client.NewAptClient(rpc)
+	if err != nil {
+		return nil, err
+	}
+	return c.ModuleABI(moduleAddress, module)
+}
+
+// decodeABI reads an AccountModule JSON or a bare MoveModuleABI JSON
+func decodeABI(b []byte) (*types.MoveModuleABI, error) {
+	var module types.AccountModule
+	if err := json.Unmarshal(b, &module); err == nil && module.ABI != nil {
+		return module.ABI, nil
+	}
+
+	abi := &types.MoveModuleABI{}
+	if err := json.Unmarshal(b, abi); err != nil {
+		return nil, err
+	}
+
+	if abi.Name == "" {
+		return nil, types.ErrABINull
+	}
+	return abi, nil
+}
@@ -0,0 +1,33 @@
+package key_manager
+
+import (
+	"golang.org/x/crypto/sha3"
+)
+
+// AuthScheme is the trailing byte appended to the key material before hashing
+// it into an authentication key, docs in https://aptos.dev/concepts/accounts
+type AuthScheme = byte
+
+const (
+	Ed25519Scheme      AuthScheme = 0x00
+	MultiEd25519Scheme AuthScheme = 0x01
+	SingleKeyScheme    AuthScheme = 0x02
+	MultiKeyScheme     AuthScheme = 0x03
+
+	// schemes of derived addresses, no key signs for them
+	UserDerivedScheme     AuthScheme = 0xFC
+	ObjectFromGuidScheme  AuthScheme = 0xFD
+	ObjectFromSeedScheme  AuthScheme = 0xFE
+	ResourceAccountScheme AuthScheme = 0xFF
+)
+
+// AuthKey returns sha3-256(data || scheme), for a fresh account the auth key is
+// the account address as well.
+func AuthKey(scheme AuthScheme, data ...[]byte) []byte {
+	hasher := sha3.New256()
+	for _, d := range data {
+		hasher.Write(d)
+	}
+	hasher.Write([]byte{scheme})
+	return hasher.Sum(nil)
+}
@@ -18,6 +18,8 @@ const (
 var (
 	ErrInvalidPath        = errors.New("invalid derivation path")
 	ErrNoPublicDerivation = errors.New("no public derivation for ed25519")
+	ErrCurveMismatched    = errors.New("key curve mismatched")
+	ErrInvalidPrivateKey  = errors.New("invalid private key")
 
 	pathRegex = regexp.MustCompile(`^m(\/[0-9]+')+$`)
 )
@@ -1,8 +1,12 @@
 package key_manager
 
 import (
+	"crypto/ed25519"
+	"encoding/binary"
 	"fmt"
 	"github.com/btcsuite/btcd/btcec"
+	"github.com/threeandtwo/aptclient/hexutil"
+	"github.com/threeandtwo/aptclient/types"
 	"github.com/tyler-smith/go-bip32"
 	"github.com/tyler-smith/go-bip39"
 	"sync"
@@ -44,10 +48,16 @@ const (
 
 type Key struct {
 	path     string
+	curve    types.CurveTy
 	bip32Key *bip32.Key
 }
 
+// EncodeEth only works for keys derived on types.Secp256k1Curve, it returns empty values for the other curves
 func (k *Key) EncodeEth() (address string, publicKey string, privateKey string) {
+	if k.curve != types.Secp256k1Curve {
+		return address, publicKey, privateKey
+	}
+
 	prvKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), k.bip32Key.Key)
 	privateKey = fmt.Sprintf("%x", prvKey.D)
 	for i := len(privateKey); i < 64; i++ {
@@ -56,6 +66,56 @@ func (k *Key) EncodeEth() (address string, publicKey string, privateKey string)
 	return address, publicKey, privateKey
 }
 
+// EncodeApt returns the account of the key, the address equals the authKey until the key is rotated.
+// Ed25519 keys use the legacy ed25519 scheme, secp256k1 keys the single key scheme
+func (k *Key) EncodeApt() (address string, publicKey string, authKey string) {
+	var pubKey []byte
+	switch k.curve {
+	case types.Ed25519Curve:
+		pubKey = k.PrivateKey().Public().(ed25519.PublicKey)
+		authKey = hexutil.Encode(AuthKey(Ed25519Scheme, pubKey))
+	case types.Secp256k1Curve:
+		pubKey = Secp256k1PublicKey(k.Secp256k1PrivateKey())
+		authKey = hexutil.Encode(Secp256k1AuthKey(pubKey))
+	default:
+		return address, publicKey, authKey
+	}
+	return authKey, hexutil.Encode(pubKey), authKey
+}
+
+// PrivateKey returns the ed25519 private key, nil for keys derived on the other curves
+func (k *Key) PrivateKey() ed25519.PrivateKey {
+	if k.curve != types.Ed25519Curve {
+		return nil
+	}
+	return ed25519.NewKeyFromSeed(k.bip32Key.Key[:ed25519.SeedSize])
+}
+
+// Secp256k1PrivateKey returns the secp256k1 private key, nil for keys derived on the other curves
+func (k *Key) Secp256k1PrivateKey() *btcec.PrivateKey {
+	if k.curve != types.Secp256k1Curve {
+		return nil
+	}
+	prvKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), k.bip32Key.Key)
+	return prvKey
+}
+
+func (k *Key) AptAccount() (*types.AptAccount, error) {
+	address, publicKey, authKey := k.EncodeApt()
+	if address == "" {
+		return nil, ErrCurveMismatched
+	}
+
+	return &types.AptAccount{
+		Address:      address,
+		PublicKey:    publicKey,
+		PrivateKey:   k.PrivateKey(),
+		AuthKey:      authKey,
+		Curve:        k.curve,
+		Secp256k1Key: k.Secp256k1PrivateKey(),
+	}, nil
+}
+
 // https://github.com/bitcoin/bips/blob/master/bip-0044.mediawiki
 // bip44 define the following 5 levels in BIP32 path:
 // m / purpose' / coin_type' / account' / change / address_index
@@ -67,6 +127,7 @@ func (k *Key) GetPath() string {
 type KeyManager struct {
 	mnemonic   string
 	passphrase string
+	curve      types.CurveTy
 	keys       map[string]*bip32.Key
 	mux        sync.Mutex
 }
@@ -125,6 +186,22 @@ func NewKeyManagerWithMnemonic(bitSize int, passphrase string, keyphrase string)
 	return km, nil
 }
 
+// WithCurve returns a key manager with the same mnemonic deriving keys on curve.
+// types.Ed25519Curve (default) derives Aptos keys by SLIP-10,
+// types.Secp256k1Curve derives BIP32 keys, see Key.EncodeEth
+func (km *KeyManager) WithCurve(curve types.CurveTy) *KeyManager {
+	return &KeyManager{
+		mnemonic:   km.mnemonic,
+		passphrase: km.passphrase,
+		curve:      curve,
+		keys:       make(map[string]*bip32.Key, 0),
+	}
+}
+
+func (km *KeyManager) GetCurve() types.CurveTy {
+	return km.curve
+}
+
 func (km *KeyManager) GetMnemonic() string {
 	return km.mnemonic
 }
@@ -160,7 +237,7 @@ func (km *KeyManager) GetMasterKey() (*bip32.Key, error) {
 		return key, nil
 	}
 
-	key, err := bip32.NewMasterKey(km.GetSeed())
+	key, err := km.newMasterKey(km.GetSeed())
 	if err != nil {
 		return nil, err
 	}
@@ -170,6 +247,64 @@ func (km *KeyManager) GetMasterKey() (*bip32.Key, error) {
 	return key, nil
 }
 
+func (km *KeyManager) newMasterKey(seed []byte) (*bip32.Key, error) {
+	if km.curve == types.Secp256k1Curve {
+		return bip32.NewMasterKey(seed)
+	}
+
+	key, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bip32.Key{
+		Key:         key.Key,
+		Version:     bip32.PrivateWalletVersion,
+		ChildNumber: []byte{0x00, 0x00, 0x00, 0x00},
+		FingerPrint: []byte{0x00, 0x00, 0x00, 0x00},
+		ChainCode:   key.ChainCode,
+		Depth:       0x0,
+		IsPrivate:   true,
+	}, nil
+}
+
+// newChildKey derives BIP32 children for secp256k1 and SLIP-10 children for ed25519,
+// ed25519 has hardened derivation only so every index is hardened for it
+func (km *KeyManager) newChildKey(parent *bip32.Key, index uint32) (*bip32.Key, error) {
+	if km.curve == types.Secp256k1Curve {
+		return parent.NewChildKey(index)
+	}
+
+	if index < Apostrophe {
+		index += Apostrophe
+	}
+
+	key, err := (&KeyApt{Key: parent.Key, ChainCode: parent.ChainCode}).Derive(index)
+	if err != nil {
+		return nil, err
+	}
+
+	childNumber := make([]byte, 4)
+	binary.BigEndian.PutUint32(childNumber, index)
+	return &bip32.Key{
+		Key:         key.Key,
+		Version:     bip32.PrivateWalletVersion,
+		ChildNumber: childNumber,
+		FingerPrint: []byte{0x00, 0x00, 0x00, 0x00},
+		ChainCode:   key.ChainCode,
+		Depth:       parent.Depth + 1,
+		IsPrivate:   true,
+	}, nil
+}
+
+// segment formats a non-hardened level of the path, it is hardened on ed25519
+func (km *KeyManager) segment(index uint32) string {
+	if km.curve == types.Secp256k1Curve {
+		return fmt.Sprintf("%d", index)
+	}
+	return fmt.Sprintf("%d'", index)
+}
+
 func (km *KeyManager) GetPurposeKey(purpose uint32) (*bip32.Key, error) {
 	path := fmt.Sprintf(`m/%d'`, purpose-Apostrophe)
 
@@ -183,7 +318,7 @@ func (km *KeyManager) GetPurposeKey(purpose uint32) (*bip32.Key, error) {
 		return nil, err
 	}
 
-	key, err = parent.NewChildKey(purpose)
+	key, err = km.newChildKey(parent, purpose)
 	if err != nil {
 		return nil, err
 	}
@@ -206,7 +341,7 @@ func (km *KeyManager) GetCoinTypeKey(purpose, coinType uint32) (*bip32.Key, erro
 		return nil, err
 	}
 
-	key, err = parent.NewChildKey(coinType)
+	key, err = km.newChildKey(parent, coinType)
 	if err != nil {
 		return nil, err
 	}
@@ -229,7 +364,7 @@ func (km *KeyManager) GetAccountKey(purpose, coinType, account uint32) (*bip32.K
 		return nil, err
 	}
 
-	key, err = parent.NewChildKey(account + Apostrophe)
+	key, err = km.newChildKey(parent, account+Apostrophe)
 	if err != nil {
 		return nil, err
 	}
@@ -244,7 +379,7 @@ func (km *KeyManager) GetAccountKey(purpose, coinType, account uint32) (*bip32.K
 // change constant 0 is used for external chain
 // change constant 1 is used for internal chain (also known as change addresses)
 func (km *KeyManager) GetChangeKey(purpose, coinType, account, change uint32) (*bip32.Key, error) {
-	path := fmt.Sprintf(`m/%d'/%d'/%d'/%d'`, purpose-Apostrophe, coinType-Apostrophe, account, change)
+	path := fmt.Sprintf(`m/%d'/%d'/%d'/%s`, purpose-Apostrophe, coinType-Apostrophe, account, km.segment(change))
 
 	key, ok := km.getKey(path)
 	if ok {
@@ -256,7 +391,7 @@ func (km *KeyManager) GetChangeKey(purpose, coinType, account, change uint32) (*
 		return nil, err
 	}
 
-	key, err = parent.NewChildKey(change)
+	key, err = km.newChildKey(parent, change)
 	if err != nil {
 		return nil, err
 	}
@@ -285,11 +420,11 @@ func (km *KeyManager) GetShortAccountKey(purpose uint32, coinType uint32, accoun
 }
 
 func (km *KeyManager) GetShortKey(purpose uint32, coinType uint32, account uint32, index uint32) (*Key, error) {
-	path := fmt.Sprintf(`m/%d'/%d'/%d'/%d'`, purpose-Apostrophe, coinType-Apostrophe, account, index)
+	path := fmt.Sprintf(`m/%d'/%d'/%d'/%s`, purpose-Apostrophe, coinType-Apostrophe, account, km.segment(index))
 
 	key, ok := km.getKey(path)
 	if ok {
-		return &Key{path: path, bip32Key: key}, nil
+		return &Key{path: path, curve: km.curve, bip32Key: key}, nil
 	}
 
 	parent, err := km.GetShortAccountKey(purpose, coinType, account)
@@ -297,22 +432,24 @@ func (km *KeyManager) GetShortKey(purpose uint32, coinType uint32, account uint3
 		return nil, err
 	}
 
-	key, err = parent.NewChildKey(index)
+	key, err = km.newChildKey(parent, index)
 	if err != nil {
 		return nil, err
 	}
 
 	km.setKey(path, key)
 
-	return &Key{path: path, bip32Key: key}, nil
+	return &Key{path: path, curve: km.curve, bip32Key: key}, nil
 }
 
+// GetKey derives m / purpose' / coin_type' / account' / change / address_index on the curve of the key manager,
+// for Aptos accounts use GetKey(PurposeBIP44, CoinTypeAPT, 0, 0, index) on types.Ed25519Curve
 func (km *KeyManager) GetKey(purpose, coinType, account, change, index uint32) (*Key, error) {
-	path := fmt.Sprintf(`m/%d'/%d'/%d'/%d'/%d'`, purpose-Apostrophe, coinType-Apostrophe, account, change, index)
+	path := fmt.Sprintf(`m/%d'/%d'/%d'/%s/%s`, purpose-Apostrophe, coinType-Apostrophe, account, km.segment(change), km.segment(index))
 
 	key, ok := km.getKey(path)
 	if ok {
-		return &Key{path: path, bip32Key: key}, nil
+		return &Key{path: path, curve: km.curve, bip32Key: key}, nil
 	}
 
 	parent, err := km.GetChangeKey(purpose, coinType, account, change)
@@ -320,12 +457,12 @@ func (km *KeyManager) GetKey(purpose, coinType, account, change, index uint32) (
 		return nil, err
 	}
 
-	key, err = parent.NewChildKey(index)
+	key, err = km.newChildKey(parent, index)
 	if err != nil {
 		return nil, err
 	}
 
 	km.setKey(path, key)
 
-	return &Key{path: path, bip32Key: key}, nil
+	return &Key{path: path, curve: km.curve, bip32Key: key}, nil
 }
@@ -0,0 +1,75 @@
+package key_manager
+
+import (
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// AnyPublicKey variant of secp256k1 ecdsa keys used by the single key scheme
+	secp256k1AnyPublicKeyVariant = 0x01
+
+	Secp256k1SignatureLength = 64
+)
+
+// NewSecp256k1Key returns a random secp256k1 private key
+func NewSecp256k1Key() (*btcec.PrivateKey, error) {
+	return btcec.NewPrivateKey(btcec.S256())
+}
+
+// Secp256k1KeyFromBytes parses a 32 bytes private key, e.g. one exported from EVM wallets
+func Secp256k1KeyFromBytes(key []byte) (*btcec.PrivateKey, error) {
+	if len(key) != btcec.PrivKeyBytesLen {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	prvKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), key)
+	return prvKey, nil
+}
+
+// Secp256k1PublicKey returns the 65 bytes uncompressed public key
+func Secp256k1PublicKey(prvKey *btcec.PrivateKey) []byte {
+	return prvKey.PubKey().SerializeUncompressed()
+}
+
+// Secp256k1AuthKey returns the single key auth key of an uncompressed secp256k1 public key,
+// sha3-256(bcs(AnyPublicKey::Secp256k1Ecdsa) || 0x02)
+func Secp256k1AuthKey(pubKey []byte) []byte {
+	return AuthKey(SingleKeyScheme, []byte{secp256k1AnyPublicKeyVariant, byte(len(pubKey))}, pubKey)
+}
+
+// SignSecp256k1 signs sha3-256(msg) and returns the 64 bytes r || s signature, s is always low
+func SignSecp256k1(prvKey *btcec.PrivateKey, msg []byte) ([]byte, error) {
+	hash := sha3.Sum256(msg)
+	sig, err := prvKey.Sign(hash[:])
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, Secp256k1SignatureLength)
+	sig.R.FillBytes(b[:32])
+	sig.S.FillBytes(b[32:])
+	return b, nil
+}
+
+// VerifySecp256k1 verifies a r || s signature over sha3-256(msg), high s signatures are rejected like on chain
+func VerifySecp256k1(pubKey, msg, sig []byte) bool {
+	if len(sig) != Secp256k1SignatureLength {
+		return false
+	}
+
+	key, err := btcec.ParsePubKey(pubKey, btcec.S256())
+	if err != nil {
+		return false
+	}
+
+	s := new(big.Int).SetBytes(sig[32:])
+	if s.Cmp(new(big.Int).Rsh(btcec.S256().N, 1)) > 0 {
+		return false
+	}
+
+	hash := sha3.Sum256(msg)
+	return (&btcec.Signature{R: new(big.Int).SetBytes(sig[:32]), S: s}).Verify(hash[:], key)
+}
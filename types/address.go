@@ -0,0 +1,125 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/threeandtwo/aptclient/bcs"
+)
+
+const AccountAddressLength = 32
+
+// AccountAddress is a 32 bytes Aptos address, docs in
+// https://github.com/aptos-foundation/AIPs/blob/main/aips/aip-40.md
+type AccountAddress [AccountAddressLength]byte
+
+var (
+	AccountZero = AccountAddress{}
+	AccountOne  = AccountAddress{31: 0x1}
+)
+
+// ParseAccountAddress parses long (0x + 64 hex), short (0x1, leading zeros trimmed)
+// and no-prefix forms, hex is case-insensitive
+func ParseAccountAddress(address string) (AccountAddress, error) {
+	var a AccountAddress
+	if address == "" {
+		return a, ErrAddressNull
+	}
+
+	h := address
+	if strings.HasPrefix(h, "0x") || strings.HasPrefix(h, "0X") {
+		h = h[2:]
+	}
+
+	if h == "" {
+		return a, ErrAddressHex
+	}
+
+	if len(h) > AccountAddressLength*2 {
+		return a, ErrAddressLen
+	}
+
+	if len(h)%2 == 1 {
+		h = "0" + h
+	}
+
+	b, err := hex.DecodeString(h)
+	if err != nil {
+		return a, ErrAddressHex
+	}
+
+	copy(a[AccountAddressLength-len(b):], b)
+	return a, nil
+}
+
+// MustParseAccountAddress is ParseAccountAddress for constant addresses, it panics on invalid input
+func MustParseAccountAddress(address string) AccountAddress {
+	a, err := ParseAccountAddress(address)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// IsSpecial reports whether a is one of 0x0 to 0xf, the addresses reserved for the framework
+func (a AccountAddress) IsSpecial() bool {
+	for _, b := range a[:AccountAddressLength-1] {
+		if b != 0 {
+			return false
+		}
+	}
+	return a[AccountAddressLength-1] < 0x10
+}
+
+// String returns the AIP-40 form, short for special addresses and long for the others
+func (a AccountAddress) String() string {
+	if a.IsSpecial() {
+		return a.StringShort()
+	}
+	return a.StringLong()
+}
+
+// StringLong returns 0x followed by 64 hex characters
+func (a AccountAddress) StringLong() string {
+	return "0x" + hex.EncodeToString(a[:])
+}
+
+// StringShort returns the address with leading zeros trimmed
+func (a AccountAddress) StringShort() string {
+	h := strings.TrimLeft(hex.EncodeToString(a[:]), "0")
+	if h == "" {
+		h = "0"
+	}
+	return "0x" + h
+}
+
+func (a AccountAddress) Bytes() []byte {
+	return a[:]
+}
+
+func (a AccountAddress) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+func (a *AccountAddress) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	address, err := ParseAccountAddress(s)
+	if err != nil {
+		return err
+	}
+	*a = address
+	return nil
+}
+
+func (a *AccountAddress) MarshalBCS(s *bcs.Serializer) {
+	s.FixedBytes(a[:])
+}
+
+func (a *AccountAddress) UnmarshalBCS(d *bcs.Deserializer) {
+	copy(a[:], d.FixedBytes(AccountAddressLength))
+}
@@ -0,0 +1,203 @@
+package types
+
+import (
+	"math/big"
+	"strings"
+)
+
+type RoundingMode int
+
+const (
+	// RoundExact rejects amounts with more fraction digits than the decimals of the coin
+	RoundExact RoundingMode = iota
+	RoundDown
+	RoundUp
+	RoundHalfUp
+	RoundHalfEven
+)
+
+// AptDecimals are the decimals of APT, 1 APT is 1e8 octas
+const AptDecimals = 8
+
+// Amount is Value base units of a coin with Decimals decimals, 150000000 octas are 1.5 APT
+type Amount struct {
+	Value    *big.Int
+	Decimals uint8
+}
+
+// NewAmount copies value, a nil value stays nil and is rejected by Rescale and U64 with ErrAmountNull
+func NewAmount(value *big.Int, decimals uint8) *Amount {
+	if value == nil {
+		return &Amount{Decimals: decimals}
+	}
+	return &Amount{Value: new(big.Int).Set(value), Decimals: decimals}
+}
+
+// ParseAmount parses a non-negative decimal like "1.5" into base units,
+// it rejects fraction digits beyond decimals
+func ParseAmount(s string, decimals uint8) (*Amount, error) {
+	return ParseAmountRounded(s, decimals, RoundExact)
+}
+
+// ParseAmountRounded is ParseAmount rounding the fraction digits beyond decimals by mode,
+// only digits with an optional single dot between them are accepted
+func ParseAmountRounded(s string, decimals uint8, mode RoundingMode) (*Amount, error) {
+	integer, fraction, hasDot := strings.Cut(s, ".")
+	if !isDigits(integer) || hasDot && !isDigits(fraction) {
+		return nil, ErrAmountFormat
+	}
+
+	value, _ := new(big.Int).SetString(integer, 10)
+	value.Mul(value, pow10(int(decimals)))
+
+	kept, dropped := fraction, ""
+	if len(fraction) > int(decimals) {
+		kept, dropped = fraction[:decimals], fraction[decimals:]
+	}
+
+	if kept != "" {
+		f, _ := new(big.Int).SetString(kept, 10)
+		value.Add(value, f.Mul(f, pow10(int(decimals)-len(kept))))
+	}
+
+	if strings.Trim(dropped, "0") != "" {
+		roundUp, err := roundUp(value, dropped, mode)
+		if err != nil {
+			return nil, err
+		}
+		if roundUp {
+			value.Add(value, big.NewInt(1))
+		}
+	}
+	return &Amount{Value: value, Decimals: decimals}, nil
+}
+
+// roundUp reports whether value, truncated by the non-zero digits dropped, rounds away from zero
+func roundUp(value *big.Int, dropped string, mode RoundingMode) (bool, error) {
+	switch mode {
+	case RoundDown:
+		return false, nil
+	case RoundUp:
+		return true, nil
+	case RoundHalfUp:
+		return dropped[0] >= '5', nil
+	case RoundHalfEven:
+		if dropped[0] != '5' {
+			return dropped[0] > '5', nil
+		}
+		if strings.Trim(dropped[1:], "0") != "" {
+			return true, nil
+		}
+		return value.Bit(0) == 1, nil
+	default:
+		return false, ErrAmountPrecision
+	}
+}
+
+// String formats the amount with trailing fraction zeros trimmed, 150000000 octas are "1.5"
+func (a *Amount) String() string {
+	s := a.StringFixed()
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// StringFixed formats the amount with exactly Decimals fraction digits, a null amount is "<nil>"
+func (a *Amount) StringFixed() string {
+	if a == nil || a.Value == nil {
+		return "<nil>"
+	}
+
+	sign := ""
+	if a.Value.Sign() < 0 {
+		sign = "-"
+	}
+	digits := new(big.Int).Abs(a.Value).String()
+	if a.Decimals == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= int(a.Decimals) {
+		digits = strings.Repeat("0", int(a.Decimals)-len(digits)+1) + digits
+	}
+	point := len(digits) - int(a.Decimals)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// Rescale returns the amount in base units of decimals, it rejects amounts with more
+// fraction digits than decimals
+func (a *Amount) Rescale(decimals uint8) (*Amount, error) {
+	if a == nil || a.Value == nil {
+		return nil, ErrAmountNull
+	}
+
+	value := new(big.Int).Set(a.Value)
+	if decimals >= a.Decimals {
+		value.Mul(value, pow10(int(decimals-a.Decimals)))
+		return &Amount{Value: value, Decimals: decimals}, nil
+	}
+
+	value, rem := value.QuoRem(value, pow10(int(a.Decimals-decimals)), new(big.Int))
+	if rem.Sign() != 0 {
+		return nil, ErrAmountPrecision
+	}
+	return &Amount{Value: value, Decimals: decimals}, nil
+}
+
+// BaseUnits returns the u64 base units of the amount for an asset of decimals, 1.5 with 6 decimals
+// is 150000000 for APT
+func (a *Amount) BaseUnits(decimals uint8) (uint64, error) {
+	rescaled, err := a.Rescale(decimals)
+	if err != nil {
+		return 0, err
+	}
+	return rescaled.U64()
+}
+
+// U64 returns Value as the u64 of Move amount arguments
+func (a *Amount) U64() (uint64, error) {
+	if a == nil || a.Value == nil {
+		return 0, ErrAmountNull
+	}
+	if a.Value.Sign() < 0 || !a.Value.IsUint64() {
+		return 0, ErrAmountOverflow
+	}
+	return a.Value.Uint64(), nil
+}
+
+// Amount returns value base units of the coin
+func (c *CoinInfo) Amount(value *big.Int) *Amount {
+	return NewAmount(value, c.Decimals)
+}
+
+// ParseAmount parses s by the decimals of the coin
+func (c *CoinInfo) ParseAmount(s string) (*Amount, error) {
+	return ParseAmount(s, c.Decimals)
+}
+
+// Amount returns value base units of the fungible asset
+func (m *FungibleAssetMetadata) Amount(value *big.Int) *Amount {
+	return NewAmount(value, m.Decimals)
+}
+
+// ParseAmount parses s by the decimals of the fungible asset
+func (m *FungibleAssetMetadata) ParseAmount(s string) (*Amount, error) {
+	return ParseAmount(s, m.Decimals)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
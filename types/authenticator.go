@@ -0,0 +1,220 @@
+package types
+
+import (
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/threeandtwo/aptclient/bcs"
+)
+
+// BCS variants of the authenticator enums,
+// docs in https://github.com/aptos-foundation/AIPs/blob/main/aips/aip-55.md
+const (
+	txAuthEd25519Variant      = 0
+	txAuthSingleSenderVariant = 4
+
+	accountAuthSingleKeyVariant = 2
+	accountAuthMultiKeyVariant  = 3
+
+	anyKeyEd25519Variant   = 0
+	anyKeySecp256k1Variant = 1
+
+	// MaxMultiKeyPublicKeys is the most keys a MultiKey account holds
+	MaxMultiKeyPublicKeys = 32
+)
+
+// AccountSignature is the sender of a single_sender signature,
+// PublicKey and Signature are set for single_key_signature,
+// PublicKeys, Signatures and SignaturesRequired for multi_key_signature
+type AccountSignature struct {
+	Type               string              `json:"type"`
+	PublicKey          *AnyPublicKey       `json:"public_key,omitempty"`
+	Signature          *AnySignature       `json:"signature,omitempty"`
+	PublicKeys         []*AnyPublicKey     `json:"public_keys,omitempty"`
+	Signatures         []*IndexedSignature `json:"signatures,omitempty"`
+	SignaturesRequired uint8               `json:"signatures_required,omitempty"`
+}
+
+type AnyPublicKey struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type AnySignature struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type IndexedSignature struct {
+	Index     uint8         `json:"index"`
+	Signature *AnySignature `json:"signature"`
+}
+
+// MultiKey is the public key of a MultiKey account, any SignaturesRequired of PublicKeys sign for it
+type MultiKey struct {
+	PublicKeys         []*AnyPublicKey
+	SignaturesRequired uint8
+}
+
+func (t *TxSignature) MarshalBCS(s *bcs.Serializer) {
+	switch t.Type {
+	case Ed25519:
+		s.Uleb128(txAuthEd25519Variant)
+		s.WriteBytes(decodeHex(s, t.PublicKey))
+		s.WriteBytes(decodeHex(s, t.Signature))
+	case SingleSender:
+		if t.Sender == nil {
+			s.SetError(ErrSignNull)
+			return
+		}
+		s.Uleb128(txAuthSingleSenderVariant)
+		s.Struct(t.Sender)
+	default:
+		s.SetError(ErrUnsupportedSignature)
+	}
+}
+
+// ForSimulation returns a copy with every signature zeroed, the simulation API rejects valid signatures
+func (t *TxSignature) ForSimulation() *TxSignature {
+	sig := *t
+	if sig.Signature != "" {
+		sig.Signature = zeroHex(sig.Signature)
+	}
+	if t.Sender != nil {
+		sig.Sender = t.Sender.ForSimulation()
+	}
+	return &sig
+}
+
+func (a *AccountSignature) MarshalBCS(s *bcs.Serializer) {
+	switch a.Type {
+	case SingleKeySignature:
+		if a.PublicKey == nil || a.Signature == nil {
+			s.SetError(ErrSignNull)
+			return
+		}
+		s.Uleb128(accountAuthSingleKeyVariant)
+		s.Struct(a.PublicKey)
+		s.Struct(a.Signature)
+	case MultiKeySignature:
+		s.Uleb128(accountAuthMultiKeyVariant)
+		s.Struct(&MultiKey{PublicKeys: a.PublicKeys, SignaturesRequired: a.SignaturesRequired})
+
+		signatures := a.sortedSignatures()
+		indexes := make([]uint8, 0, len(signatures))
+		s.Uleb128(uint32(len(signatures)))
+		for _, sig := range signatures {
+			if sig.Signature == nil {
+				s.SetError(ErrSignNull)
+				return
+			}
+			s.Struct(sig.Signature)
+			indexes = append(indexes, sig.Index)
+		}
+
+		bitmap, err := MultiKeyBitmap(indexes, len(a.PublicKeys))
+		if err != nil {
+			s.SetError(err)
+			return
+		}
+		s.WriteBytes(bitmap)
+	default:
+		s.SetError(ErrUnsupportedSignature)
+	}
+}
+
+func (a *AccountSignature) ForSimulation() *AccountSignature {
+	sig := *a
+	if a.Signature != nil {
+		sig.Signature = a.Signature.ForSimulation()
+	}
+
+	sig.Signatures = nil
+	for _, s := range a.Signatures {
+		sig.Signatures = append(sig.Signatures, &IndexedSignature{Index: s.Index, Signature: s.Signature.ForSimulation()})
+	}
+	return &sig
+}
+
+// sortedSignatures orders signatures by key index, the order the bitmap is read in
+func (a *AccountSignature) sortedSignatures() []*IndexedSignature {
+	signatures := make([]*IndexedSignature, len(a.Signatures))
+	copy(signatures, a.Signatures)
+	sort.Slice(signatures, func(i, j int) bool {
+		return signatures[i].Index < signatures[j].Index
+	})
+	return signatures
+}
+
+func (k *AnyPublicKey) MarshalBCS(s *bcs.Serializer) {
+	switch k.Type {
+	case AnyEd25519:
+		s.Uleb128(anyKeyEd25519Variant)
+	case Secp256k1Ecdsa:
+		s.Uleb128(anyKeySecp256k1Variant)
+	default:
+		s.SetError(ErrUnsupportedPublicKey)
+		return
+	}
+	s.WriteBytes(decodeHex(s, k.Value))
+}
+
+func (k *AnySignature) MarshalBCS(s *bcs.Serializer) {
+	switch k.Type {
+	case AnyEd25519:
+		s.Uleb128(anyKeyEd25519Variant)
+	case Secp256k1Ecdsa:
+		s.Uleb128(anyKeySecp256k1Variant)
+	default:
+		s.SetError(ErrUnsupportedSignature)
+		return
+	}
+	s.WriteBytes(decodeHex(s, k.Value))
+}
+
+func (k *AnySignature) ForSimulation() *AnySignature {
+	if k == nil {
+		return nil
+	}
+	return &AnySignature{Type: k.Type, Value: zeroHex(k.Value)}
+}
+
+func (k *MultiKey) MarshalBCS(s *bcs.Serializer) {
+	if len(k.PublicKeys) == 0 || len(k.PublicKeys) > MaxMultiKeyPublicKeys {
+		s.SetError(ErrMultiKeyCount)
+		return
+	}
+	bcs.SerializeSequence(s, k.PublicKeys)
+	s.U8(k.SignaturesRequired)
+}
+
+// MultiKeyBitmap sets bit i (from the most significant bit of the first byte) for every signing key index
+func MultiKeyBitmap(indexes []uint8, numKeys int) ([]byte, error) {
+	bitmap := make([]byte, (numKeys+7)/8)
+	for _, i := range indexes {
+		if int(i) >= numKeys {
+			return nil, ErrMultiKeyIndex
+		}
+		bitmap[i/8] |= 0x80 >> (i % 8)
+	}
+	return bitmap, nil
+}
+
+// decodeHex decodes hex values with or without 0x, ed25519 signatures are written without it
+func decodeHex(s *bcs.Serializer, v string) []byte {
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(v, "0x"), "0X"))
+	if err != nil {
+		s.SetError(err)
+		return nil
+	}
+	return b
+}
+
+func zeroHex(v string) string {
+	prefix := ""
+	if strings.HasPrefix(v, "0x") || strings.HasPrefix(v, "0X") {
+		prefix, v = v[:2], v[2:]
+	}
+	return prefix + strings.Repeat("0", len(v))
+}
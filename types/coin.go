@@ -0,0 +1,67 @@
+package types
+
+import "math/big"
+
+// CoinStore is the data of a 0x1::coin::CoinStore<T> resource
+type CoinStore struct {
+	Coin struct {
+		Value string `json:"value"`
+	} `json:"coin"`
+	Frozen         bool        `json:"frozen"`
+	DepositEvents  EventHandle `json:"deposit_events"`
+	WithdrawEvents EventHandle `json:"withdraw_events"`
+}
+
+// EventHandle is a 0x1::event::EventHandle<T>, the guid of its v1 events
+type EventHandle struct {
+	Counter string `json:"counter"`
+	Guid    struct {
+		Id struct {
+			CreationNum string `json:"creation_num"`
+			Addr        string `json:"addr"`
+		} `json:"id"`
+	} `json:"guid"`
+}
+
+type CoinBalance struct {
+	CoinType string
+	Value    *big.Int
+	Frozen   bool
+}
+
+// CoinInfo is the data of a 0x1::coin::CoinInfo<T> resource, Supply is nil for coins without supply tracking
+type CoinInfo struct {
+	CoinType string
+	Name     string
+	Symbol   string
+	Decimals uint8
+	Supply   *big.Int
+}
+
+// CoinInfoData is the JSON of a 0x1::coin::CoinInfo<T> resource, supply is an
+// Option<OptionalAggregator> holding either an integer or an aggregator stored in a table
+type CoinInfoData struct {
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals uint8  `json:"decimals"`
+	Supply   struct {
+		Vec []struct {
+			Aggregator struct {
+				Vec []struct {
+					Handle string `json:"handle"`
+					Key    string `json:"key"`
+				} `json:"vec"`
+			} `json:"aggregator"`
+			Integer struct {
+				Vec []struct {
+					Value string `json:"value"`
+				} `json:"vec"`
+			} `json:"integer"`
+		} `json:"vec"`
+	} `json:"supply"`
+}
+
+// DirectTransferConfig is the data of a 0x1::aptos_account::DirectTransferConfig resource
+type DirectTransferConfig struct {
+	AllowArbitraryCoinTransfers bool `json:"allow_arbitrary_coin_transfers"`
+}
@@ -0,0 +1,83 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/threeandtwo/aptclient/bcs"
+)
+
+type ModuleId struct {
+	Address AccountAddress
+	Name    string
+}
+
+// EntryFunction is the BCS form of an entry or view function call, Args are the BCS bytes of every argument
+type EntryFunction struct {
+	Module   ModuleId
+	Function string
+	TypeArgs []*TypeTag
+	Args     [][]byte
+}
+
+// ParseFunctionId splits address::module::name
+func ParseFunctionId(function string) (*ModuleId, string, error) {
+	parts := strings.Split(function, "::")
+	if len(parts) != 3 || !isIdentifier(parts[1]) || !isIdentifier(parts[2]) {
+		return nil, "", ErrFunctionId
+	}
+
+	address, err := ParseAccountAddress(parts[0])
+	if err != nil {
+		return nil, "", err
+	}
+	return &ModuleId{Address: address, Name: parts[1]}, parts[2], nil
+}
+
+// NewEntryFunction returns the call of function with the BCS bytes of its args
+func NewEntryFunction(function string, typeArgs []string, args [][]byte) (*EntryFunction, error) {
+	module, name, err := ParseFunctionId(function)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := ParseTypeTags(typeArgs)
+	if err != nil {
+		return nil, err
+	}
+	return &EntryFunction{Module: *module, Function: name, TypeArgs: tags, Args: args}, nil
+}
+
+func (m *ModuleId) String() string {
+	return m.Address.String() + "::" + m.Name
+}
+
+func (m *ModuleId) MarshalBCS(s *bcs.Serializer) {
+	s.Struct(&m.Address)
+	s.WriteStr(m.Name)
+}
+
+func (m *ModuleId) UnmarshalBCS(d *bcs.Deserializer) {
+	d.Struct(&m.Address)
+	m.Name = d.ReadStr()
+}
+
+func (e *EntryFunction) MarshalBCS(s *bcs.Serializer) {
+	s.Struct(&e.Module)
+	s.WriteStr(e.Function)
+	bcs.SerializeSequence(s, e.TypeArgs)
+	s.Uleb128(uint32(len(e.Args)))
+	for _, arg := range e.Args {
+		s.WriteBytes(arg)
+	}
+}
+
+func (e *EntryFunction) UnmarshalBCS(d *bcs.Deserializer) {
+	d.Struct(&e.Module)
+	e.Function = d.ReadStr()
+	e.TypeArgs = bcs.DeserializeSequence(d, func() *TypeTag { return &TypeTag{} })
+	n := d.Uleb128()
+	e.Args = nil
+	for i := uint32(0); i < n && d.Error() == nil; i++ {
+		e.Args = append(e.Args, d.ReadBytes())
+	}
+}
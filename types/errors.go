@@ -3,20 +3,73 @@ package types
 import "errors"
 
 var (
-	ErrNotPrivateKeyTy = errors.New("params not privateKey")
-	ErrNotNoneTy       = errors.New("params mismatched")
-	ErrNotMnemonicTy   = errors.New("params not mnemonic")
-	ErrMnemonicIndex   = errors.New("index must be ge 0 for mnemonic")
-	ErrMnemonicCount   = errors.New("mnemonic count should be 15 | 18 | 21 | 24")
+	ErrNotPrivateKeyTy   = errors.New("params not privateKey")
+	ErrNotNoneTy         = errors.New("params mismatched")
+	ErrNotMnemonicTy     = errors.New("params not mnemonic")
+	ErrMnemonicIndex     = errors.New("index must be ge 0 for mnemonic")
+	ErrMnemonicCount     = errors.New("mnemonic count should be 15 | 18 | 21 | 24")
+	ErrSecp256k1KeyNull  = errors.New("secp256k1 private key is null")
+	ErrKeyNotLoaded      = errors.New("private key is not loaded, get the account first")
+	ErrMessagePrefix     = errors.New("message prefix should be APTOS")
+	ErrFullMessage       = errors.New("full message mismatched with the signed fields")
+	ErrPublicKeyLen      = errors.New("public key length mismatched")
+	ErrAuthKeyMismatched = errors.New("public key mismatched with the auth key of the account")
+	ErrAddressMismatched = errors.New("address mismatched")
+	ErrVanityPattern     = errors.New("vanity prefix and suffix should be hex and at most 64 characters in total")
 
-	ErrRpcNull          = errors.New("rps address is null")
-	ErrAddressNull      = errors.New("address is null")
-	ErrAddressLen       = errors.New("address length != 66")
-	ErrResourceTypeNull = errors.New("resource type is null")
-	ErrParsedValue      = errors.New("value type is mismatched")
-	ErrModuleIdNull     = errors.New("moduleId is null")
-	ErrHashNull         = errors.New("hash is null")
-	ErrSignNull         = errors.New("signature is null")
-	ErrPayloadNull      = errors.New("payload is null")
-	ErrRequestRpc       = errors.New("request REST API error")
+	ErrRpcNull           = errors.New("rps address is null")
+	ErrAddressNull       = errors.New("address is null")
+	ErrAddressLen        = errors.New("address should be at most 64 hex characters")
+	ErrAddressHex        = errors.New("address is not a hex string")
+	ErrResourceTypeNull  = errors.New("resource type is null")
+	ErrResourceNotFound  = errors.New("resource not found")
+	ErrTableItemNotFound = errors.New("table item not found")
+	ErrTableItemNull     = errors.New("table handle | key type | value type | key is null")
+	ErrCoinTypeNull      = errors.New("coin type is null")
+	ErrAmountFormat      = errors.New("amount should be digits with an optional fraction like 1.5")
+	ErrAmountPrecision   = errors.New("amount has more fraction digits than the coin decimals")
+	ErrAmountOverflow    = errors.New("amount is out of u64 range")
+	ErrAmountNull        = errors.New("amount is null")
+	ErrBatchTransferLen  = errors.New("batch transfer needs as many amounts as recipients")
+	ErrCoinNotAccepted   = errors.New("recipient does not accept the coin")
+	ErrTypeTag           = errors.New("invalid move type")
+	ErrFunctionId        = errors.New("function should be address::module::name")
+	ErrMoveArgument      = errors.New("unsupported move argument")
+	ErrViewValues        = errors.New("view returned fewer values than expected")
+	ErrABINull           = errors.New("module abi is null")
+	ErrByteCodeNull      = errors.New("module bytecode is null")
+	ErrPackageEmpty      = errors.New("package has no modules")
+	ErrPackageTooLarge   = errors.New("package exceeds the publish size limit, publish it in chunks")
+	ErrModuleCycle       = errors.New("modules of the package depend on each other in a cycle")
+	ErrStagingArea       = errors.New("staging area holds chunks of another package, clean it up first")
+	ErrTxNotFound        = errors.New("transaction not found")
+	ErrTxFailed          = errors.New("transaction failed")
+	ErrTxRawNull         = errors.New("transaction is not decoded from JSON")
+	ErrTxPending         = errors.New("transaction is not committed")
+	ErrTxExpired         = errors.New("transaction expired before it was committed")
+	ErrVersionPruned     = errors.New("ledger version is pruned from the node")
+	ErrVersionAhead      = errors.New("start version is ahead of the ledger")
+	ErrPageCursor        = errors.New("page item has no version or sequence number")
+	ErrEventData         = errors.New("event data mismatched with the registered type")
+	ErrEventStore        = errors.New("store of the event is not found")
+	ErrFunctionNotFound  = errors.New("function is not exposed by the module")
+	ErrNotEntryFunction  = errors.New("function is not an entry function")
+	ErrTypeArgumentCount = errors.New("type argument count mismatched")
+	ErrArgumentCount     = errors.New("argument count mismatched")
+	ErrArgumentType      = errors.New("argument mismatched with the param type")
+	ErrParsedValue       = errors.New("value type is mismatched")
+	ErrModuleIdNull      = errors.New("moduleId is null")
+	ErrHashNull          = errors.New("hash is null")
+	ErrSignNull          = errors.New("signature is null")
+	ErrPayloadNull       = errors.New("payload is null")
+	ErrPayloadBCS        = errors.New("payload has no BCS form, use a script or an entry function")
+	ErrScriptArgument    = errors.New("script argument value mismatched with its type")
+	ErrRequestRpc        = errors.New("request REST API error")
+
+	ErrUnsupportedSignature = errors.New("signature type is unsupported")
+	ErrUnsupportedPublicKey = errors.New("public key type is unsupported")
+	ErrMultiKeyCount        = errors.New("multi key count should be in [1, 32]")
+	ErrMultiKeyIndex        = errors.New("multi key index out of range")
+	ErrMultiKeyThreshold    = errors.New("signatures required should be in [1, multi key count]")
+	ErrMultiKeySigners      = errors.New("not enough signers for multi key")
 )
@@ -0,0 +1,224 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// EventGuid identifies the event handle of a v1 event, v2 module events have creation number 0 at 0x0
+type EventGuid struct {
+	CreationNumber U64    `json:"creation_number"`
+	AccountAddress string `json:"account_address"`
+}
+
+// EventRegistry maps Move event types to the Go structs their data decodes into,
+// generic events are looked up by their full type first and then without type arguments
+type EventRegistry struct {
+	mu     sync.RWMutex
+	events map[string]reflect.Type
+}
+
+// DefaultEventRegistry decodes the events of TxEvents and Event, it holds the common framework events
+var DefaultEventRegistry = NewEventRegistry()
+
+func init() {
+	for moveType, v := range map[string]interface{}{
+		"0x1::coin::WithdrawEvent":                                 CoinAmountEvent{},
+		"0x1::coin::DepositEvent":                                  CoinAmountEvent{},
+		"0x1::coin::CoinWithdraw":                                  CoinEvent{},
+		"0x1::coin::CoinDeposit":                                   CoinEvent{},
+		"0x1::fungible_asset::Withdraw":                            FAEvent{},
+		"0x1::fungible_asset::Deposit":                             FAEvent{},
+		"0x1::fungible_asset::Frozen":                              FAFrozenEvent{},
+		"0x1::transaction_fee::FeeStatement":                       FeeStatement{},
+		"0x1::account::CoinRegisterEvent":                          CoinRegisterEvent{},
+		"0x1::account::KeyRotationEvent":                           KeyRotationEvent{},
+		"0x1::account::KeyRotation":                                KeyRotationEvent{},
+		"0x1::object::TransferEvent":                               ObjectTransferEvent{},
+		"0x1::object::Transfer":                                    ObjectTransferEvent{},
+		"0x1::block::NewBlockEvent":                                NewBlockEvent{},
+		"0x1::block::NewBlock":                                     NewBlockEvent{},
+		"0x1::reconfiguration::NewEpochEvent":                      NewEpochEvent{},
+		"0x1::reconfiguration::NewEpoch":                           NewEpochEvent{},
+		"0x1::aptos_account::DirectCoinTransferConfigUpdatedEvent": DirectTransferConfigEvent{},
+		"0x1::aptos_account::DirectCoinTransferConfigUpdated":      DirectTransferConfigEvent{},
+	} {
+		DefaultEventRegistry.Register(moveType, v)
+	}
+}
+
+func NewEventRegistry() *EventRegistry {
+	return &EventRegistry{events: make(map[string]reflect.Type)}
+}
+
+// RegisterEvent maps moveType to the type of v in DefaultEventRegistry
+func RegisterEvent(moveType string, v interface{}) {
+	DefaultEventRegistry.Register(moveType, v)
+}
+
+// Register maps moveType to the type of v, a struct or a pointer to one, later registrations replace earlier ones
+func (r *EventRegistry) Register(moveType string, v interface{}) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events[normalizeEventType(moveType)] = t
+}
+
+// Lookup returns the Go type registered for moveType
+func (r *EventRegistry) Lookup(moveType string) (reflect.Type, bool) {
+	moveType = normalizeEventType(moveType)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if t, ok := r.events[moveType]; ok && t != nil {
+		return t, true
+	}
+
+	if i := strings.IndexByte(moveType, '<'); i > 0 {
+		t, ok := r.events[moveType[:i]]
+		return t, ok && t != nil
+	}
+	return nil, false
+}
+
+// Decode decodes data into a new value of the type registered for moveType and returns a pointer to it,
+// false when moveType is not registered
+func (r *EventRegistry) Decode(moveType string, data json.RawMessage) (interface{}, bool, error) {
+	t, ok := r.Lookup(moveType)
+	if !ok {
+		return nil, false, nil
+	}
+
+	v := reflect.New(t).Interface()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, true, fmt.Errorf("%w: %s: %v", ErrEventData, moveType, err)
+	}
+	return v, true, nil
+}
+
+// normalizeEventType prints the addresses of moveType in their AIP-40 form, so 0x1 and its long form match
+func normalizeEventType(moveType string) string {
+	tag, err := ParseTypeTag(moveType)
+	if err != nil {
+		return moveType
+	}
+	return tag.String()
+}
+
+func (e *TxEvents) UnmarshalJSON(b []byte) error {
+	type txEvents TxEvents
+	if err := json.Unmarshal(b, (*txEvents)(e)); err != nil {
+		return err
+	}
+	e.Decoded = decodeEvent(e.Type, e.Data)
+	return nil
+}
+
+// Decode decodes the data of e into v
+func (e *TxEvents) Decode(v interface{}) error {
+	return json.Unmarshal(e.Data, v)
+}
+
+func (e *Event) UnmarshalJSON(b []byte) error {
+	type event Event
+	if err := json.Unmarshal(b, (*event)(e)); err != nil {
+		return err
+	}
+	e.Decoded = decodeEvent(e.Type, e.Data)
+	return nil
+}
+
+// Decode decodes the data of e into v
+func (e *Event) Decode(v interface{}) error {
+	return json.Unmarshal(e.Data, v)
+}
+
+// decodeEvent decodes data by DefaultEventRegistry, data not matching the registered type is left
+// undecoded so one bad event does not fail the whole transaction
+func decodeEvent(moveType string, data json.RawMessage) interface{} {
+	v, _, err := DefaultEventRegistry.Decode(moveType, data)
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+// CoinAmountEvent is 0x1::coin::WithdrawEvent and 0x1::coin::DepositEvent of the CoinStore event handles
+type CoinAmountEvent struct {
+	Amount U64 `json:"amount"`
+}
+
+// CoinEvent is the 0x1::coin::CoinWithdraw and 0x1::coin::CoinDeposit module event
+type CoinEvent struct {
+	CoinType string         `json:"coin_type"`
+	Account  AccountAddress `json:"account"`
+	Amount   U64            `json:"amount"`
+}
+
+// FAEvent is 0x1::fungible_asset::Withdraw and Deposit, Store is the fungible store
+type FAEvent struct {
+	Store  AccountAddress `json:"store"`
+	Amount U64            `json:"amount"`
+}
+
+type FAFrozenEvent struct {
+	Store  AccountAddress `json:"store"`
+	Frozen bool           `json:"frozen"`
+}
+
+// FeeStatement is the gas breakdown of a user transaction, the fee paid is TotalChargeGasUnits times
+// the gas unit price minus StorageFeeRefundOctas
+type FeeStatement struct {
+	TotalChargeGasUnits   U64 `json:"total_charge_gas_units"`
+	ExecutionGasUnits     U64 `json:"execution_gas_units"`
+	IOGasUnits            U64 `json:"io_gas_units"`
+	StorageFeeOctas       U64 `json:"storage_fee_octas"`
+	StorageFeeRefundOctas U64 `json:"storage_fee_refund_octas"`
+}
+
+// CoinRegisterEvent is emitted when an account registers a CoinStore, ModuleName and StructName are hex
+type CoinRegisterEvent struct {
+	TypeInfo struct {
+		AccountAddress AccountAddress `json:"account_address"`
+		ModuleName     HexBytes       `json:"module_name"`
+		StructName     HexBytes       `json:"struct_name"`
+	} `json:"type_info"`
+}
+
+type KeyRotationEvent struct {
+	Account              AccountAddress `json:"account"`
+	OldAuthenticationKey HexBytes       `json:"old_authentication_key"`
+	NewAuthenticationKey HexBytes       `json:"new_authentication_key"`
+}
+
+type ObjectTransferEvent struct {
+	Object AccountAddress `json:"object"`
+	From   AccountAddress `json:"from"`
+	To     AccountAddress `json:"to"`
+}
+
+type NewBlockEvent struct {
+	Hash                     string         `json:"hash"`
+	Epoch                    U64            `json:"epoch"`
+	Round                    U64            `json:"round"`
+	Height                   U64            `json:"height"`
+	PreviousBlockVotesBitvec HexBytes       `json:"previous_block_votes_bitvec"`
+	Proposer                 AccountAddress `json:"proposer"`
+	FailedProposerIndices    []U64          `json:"failed_proposer_indices"`
+	TimeMicroseconds         U64            `json:"time_microseconds"`
+}
+
+type NewEpochEvent struct {
+	Epoch U64 `json:"epoch"`
+}
+
+type DirectTransferConfigEvent struct {
+	NewAllowDirectTransfers bool `json:"new_allow_direct_transfers"`
+}
@@ -0,0 +1,44 @@
+package types
+
+// FungibleStore is the data of a 0x1::fungible_asset::FungibleStore resource,
+// Balance is 0 when the store keeps it in a ConcurrentFungibleBalance
+type FungibleStore struct {
+	Metadata struct {
+		Inner string `json:"inner"`
+	} `json:"metadata"`
+	Balance string `json:"balance"`
+	Frozen  bool   `json:"frozen"`
+}
+
+// ConcurrentFungibleBalance is the data of a 0x1::fungible_asset::ConcurrentFungibleBalance resource
+type ConcurrentFungibleBalance struct {
+	Balance struct {
+		Value    string `json:"value"`
+		MaxValue string `json:"max_value"`
+	} `json:"balance"`
+}
+
+// FungibleAssetMetadata is the data of a 0x1::fungible_asset::Metadata resource
+type FungibleAssetMetadata struct {
+	Address    string `json:"-"`
+	Name       string `json:"name"`
+	Symbol     string `json:"symbol"`
+	Decimals   uint8  `json:"decimals"`
+	IconUri    string `json:"icon_uri"`
+	ProjectUri string `json:"project_uri"`
+}
+
+// CoinConversionMap is the data of the 0x1::coin::CoinConversionMap resource,
+// the table maps the TypeInfo of a coin to its paired metadata address
+type CoinConversionMap struct {
+	CoinToFungibleAssetMap struct {
+		Handle string `json:"handle"`
+	} `json:"coin_to_fungible_asset_map"`
+}
+
+// ObjectCore is the data of a 0x1::object::ObjectCore resource
+type ObjectCore struct {
+	Owner                string `json:"owner"`
+	AllowUngatedTransfer bool   `json:"allow_ungated_transfer"`
+	GuidCreationNum      string `json:"guid_creation_num"`
+}
@@ -0,0 +1,96 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+
+	"github.com/threeandtwo/aptclient/hexutil"
+)
+
+// U64 is a Move u64, its JSON is a decimal string
+type U64 uint64
+
+func (u U64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatUint(uint64(u), 10))
+}
+
+func (u *U64) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*u = U64(v)
+	return nil
+}
+
+// Uint is a Move u128 or u256, its JSON is a decimal string
+type Uint struct {
+	big.Int
+}
+
+func (u Uint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+func (u *Uint) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	if _, ok := u.SetString(s, 10); !ok || u.Sign() < 0 {
+		return ErrParsedValue
+	}
+	return nil
+}
+
+// HexBytes is a Move vector<u8>, its JSON is a 0x hex string
+type HexBytes []byte
+
+func (h HexBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hexutil.Encode(h))
+}
+
+func (h *HexBytes) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	if s == "0x" {
+		*h = HexBytes{}
+		return nil
+	}
+
+	v, err := hexutil.Decode(s)
+	if err != nil {
+		return err
+	}
+	*h = v
+	return nil
+}
+
+// Object is a 0x1::object::Object<T>
+type Object struct {
+	Inner AccountAddress `json:"inner"`
+}
+
+// Option is a 0x1::option::Option<T>, Vec holds at most one value
+type Option[T any] struct {
+	Vec []T `json:"vec"`
+}
+
+// Get returns the value and whether it is set
+func (o Option[T]) Get() (T, bool) {
+	var v T
+	if len(o.Vec) == 0 {
+		return v, false
+	}
+	return o.Vec[0], true
+}
@@ -0,0 +1,136 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/threeandtwo/aptclient/bcs"
+	"golang.org/x/crypto/sha3"
+)
+
+// RawTransactionSalt prefixes the signing message of a transaction as sha3-256(RawTransactionSalt)
+const RawTransactionSalt = "APTOS::RawTransaction"
+
+// BCS variants of TransactionPayload
+const (
+	txPayloadScriptVariant        = 0
+	txPayloadEntryFunctionVariant = 2
+)
+
+// RawTransaction is the BCS form of an unsigned transaction, Payload is a *Script or an *EntryFunction
+type RawTransaction struct {
+	Sender         AccountAddress
+	SequenceNumber uint64
+	Payload        bcs.Marshaler
+	MaxGasAmount   uint64
+	GasUnitPrice   uint64
+	ExpirationTime uint64
+	ChainId        uint8
+}
+
+// SignedTransaction is the BCS body the transactions API accepts as application/x.aptos.signed_transaction+bcs
+type SignedTransaction struct {
+	Raw       *RawTransaction
+	Signature *TxSignature
+}
+
+// NewRawTransaction returns the BCS form of tx on chainId, the payload must be a *Script or an *EntryFunction
+func NewRawTransaction(tx *UnsignedTx, chainId uint8) (*RawTransaction, error) {
+	if tx.Payload == nil {
+		return nil, ErrPayloadNull
+	}
+
+	sender, err := ParseAccountAddress(tx.Sender)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, ok := tx.Payload.(bcs.Marshaler)
+	if !ok || txPayloadVariant(payload) < 0 {
+		return nil, fmt.Errorf("%w: %T", ErrPayloadBCS, tx.Payload)
+	}
+
+	return &RawTransaction{
+		Sender:         sender,
+		SequenceNumber: tx.SequenceNumber,
+		Payload:        payload,
+		MaxGasAmount:   tx.MaxGasAmount,
+		GasUnitPrice:   tx.GasUnitPrice,
+		ExpirationTime: tx.ExpirationTime,
+		ChainId:        chainId,
+	}, nil
+}
+
+func txPayloadVariant(payload bcs.Marshaler) int {
+	switch payload.(type) {
+	case *Script:
+		return txPayloadScriptVariant
+	case *EntryFunction:
+		return txPayloadEntryFunctionVariant
+	}
+	return -1
+}
+
+// SigningMessage returns sha3-256(RawTransactionSalt) || bcs(r), the message senders sign
+func (r *RawTransaction) SigningMessage() ([]byte, error) {
+	b, err := bcs.Serialize(r)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := sha3.Sum256([]byte(RawTransactionSalt))
+	return append(salt[:], b...), nil
+}
+
+func (r *RawTransaction) MarshalBCS(s *bcs.Serializer) {
+	s.Struct(&r.Sender)
+	s.U64(r.SequenceNumber)
+
+	variant := -1
+	if r.Payload != nil {
+		variant = txPayloadVariant(r.Payload)
+	}
+	if variant < 0 {
+		s.SetError(fmt.Errorf("%w: %T", ErrPayloadBCS, r.Payload))
+		return
+	}
+	s.Uleb128(uint32(variant))
+	s.Struct(r.Payload)
+
+	s.U64(r.MaxGasAmount)
+	s.U64(r.GasUnitPrice)
+	s.U64(r.ExpirationTime)
+	s.U8(r.ChainId)
+}
+
+func (r *RawTransaction) UnmarshalBCS(d *bcs.Deserializer) {
+	d.Struct(&r.Sender)
+	r.SequenceNumber = d.U64()
+
+	switch variant := d.Uleb128(); variant {
+	case txPayloadScriptVariant:
+		script := &Script{}
+		d.Struct(script)
+		r.Payload = script
+	case txPayloadEntryFunctionVariant:
+		entryFunction := &EntryFunction{}
+		d.Struct(entryFunction)
+		r.Payload = entryFunction
+	default:
+		d.SetError(fmt.Errorf("%w: variant %d", ErrPayloadBCS, variant))
+		return
+	}
+
+	r.MaxGasAmount = d.U64()
+	r.GasUnitPrice = d.U64()
+	r.ExpirationTime = d.U64()
+	r.ChainId = d.U8()
+}
+
+func (t *SignedTransaction) MarshalBCS(s *bcs.Serializer) {
+	if t.Raw == nil || t.Signature == nil {
+		s.SetError(ErrSignNull)
+		return
+	}
+	s.Struct(t.Raw)
+	s.Struct(t.Signature)
+}
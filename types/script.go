@@ -0,0 +1,224 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/threeandtwo/aptclient/bcs"
+)
+
+// ScriptArgumentTy is the BCS variant of a script argument
+type ScriptArgumentTy uint8
+
+const (
+	ScriptArgU8 ScriptArgumentTy = iota
+	ScriptArgU64
+	ScriptArgU128
+	ScriptArgAddress
+	ScriptArgU8Vector
+	ScriptArgBool
+	ScriptArgU16
+	ScriptArgU32
+	ScriptArgU256
+	// ScriptArgSerialized holds the BCS bytes of any other argument like vector<address> or String
+	ScriptArgSerialized
+)
+
+// ScriptArgument is a typed script argument, Value is the uint8, uint16, uint32 or uint64 of
+// the matching type, *big.Int for u128 and u256, AccountAddress, bool or []byte for u8 vectors
+// and serialized arguments
+type ScriptArgument struct {
+	Type  ScriptArgumentTy
+	Value interface{}
+}
+
+// MoveScriptBytecode is the compiled script of a script payload
+type MoveScriptBytecode struct {
+	Bytecode string `json:"bytecode"`
+}
+
+// ScriptPayload is the JSON form of a script transaction payload
+type ScriptPayload struct {
+	Type          string             `json:"type"`
+	Code          MoveScriptBytecode `json:"code"`
+	TypeArguments []string           `json:"type_arguments"`
+	Arguments     []interface{}      `json:"arguments"`
+}
+
+// Script is a compiled Move script with its type arguments and arguments, it is a transaction
+// payload of both the JSON and the BCS APIs, in JSON it is the ScriptPayload
+type Script struct {
+	Code     []byte
+	TypeArgs []*TypeTag
+	Args     []*ScriptArgument
+}
+
+// NewScript returns the call of the script code with typeArgs and args
+func NewScript(code []byte, typeArgs []string, args []*ScriptArgument) (*Script, error) {
+	if len(code) == 0 {
+		return nil, ErrByteCodeNull
+	}
+
+	tags, err := ParseTypeTags(typeArgs)
+	if err != nil {
+		return nil, err
+	}
+	return &Script{Code: code, TypeArgs: tags, Args: args}, nil
+}
+
+// Payload returns the JSON form of s, serialized arguments have no JSON form
+func (s *Script) Payload() (*ScriptPayload, error) {
+	typeArgs := make([]string, 0, len(s.TypeArgs))
+	for _, tag := range s.TypeArgs {
+		typeArgs = append(typeArgs, tag.String())
+	}
+
+	args := make([]interface{}, 0, len(s.Args))
+	for i, arg := range s.Args {
+		v, err := arg.JSONValue()
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i, err)
+		}
+		args = append(args, v)
+	}
+
+	return &ScriptPayload{
+		Type:          ScriptPayloadTy,
+		Code:          MoveScriptBytecode{Bytecode: "0x" + hex.EncodeToString(s.Code)},
+		TypeArguments: typeArgs,
+		Arguments:     args,
+	}, nil
+}
+
+func (s *Script) MarshalJSON() ([]byte, error) {
+	payload, err := s.Payload()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(payload)
+}
+
+func (s *Script) MarshalBCS(ser *bcs.Serializer) {
+	ser.WriteBytes(s.Code)
+	bcs.SerializeSequence(ser, s.TypeArgs)
+	bcs.SerializeSequence(ser, s.Args)
+}
+
+func (s *Script) UnmarshalBCS(d *bcs.Deserializer) {
+	s.Code = d.ReadBytes()
+	s.TypeArgs = bcs.DeserializeSequence(d, func() *TypeTag { return &TypeTag{} })
+	s.Args = bcs.DeserializeSequence(d, func() *ScriptArgument { return &ScriptArgument{} })
+}
+
+// JSONValue returns the value of a in the JSON API encoding: u64 and wider are decimal strings,
+// addresses and u8 vectors 0x hex
+func (a *ScriptArgument) JSONValue() (interface{}, error) {
+	switch v := a.Value.(type) {
+	case uint8:
+		if a.Type == ScriptArgU8 {
+			return v, nil
+		}
+	case uint16:
+		if a.Type == ScriptArgU16 {
+			return v, nil
+		}
+	case uint32:
+		if a.Type == ScriptArgU32 {
+			return v, nil
+		}
+	case uint64:
+		if a.Type == ScriptArgU64 {
+			return fmt.Sprintf("%d", v), nil
+		}
+	case *big.Int:
+		if (a.Type == ScriptArgU128 || a.Type == ScriptArgU256) && v != nil {
+			return v.String(), nil
+		}
+	case AccountAddress:
+		if a.Type == ScriptArgAddress {
+			return v.String(), nil
+		}
+	case bool:
+		if a.Type == ScriptArgBool {
+			return v, nil
+		}
+	case []byte:
+		if a.Type == ScriptArgU8Vector {
+			return "0x" + hex.EncodeToString(v), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d %T", ErrScriptArgument, a.Type, a.Value)
+}
+
+func (a *ScriptArgument) MarshalBCS(s *bcs.Serializer) {
+	s.Uleb128(uint32(a.Type))
+
+	ok := false
+	switch v := a.Value.(type) {
+	case uint8:
+		ok = a.Type == ScriptArgU8
+		s.U8(v)
+	case uint16:
+		ok = a.Type == ScriptArgU16
+		s.U16(v)
+	case uint32:
+		ok = a.Type == ScriptArgU32
+		s.U32(v)
+	case uint64:
+		ok = a.Type == ScriptArgU64
+		s.U64(v)
+	case *big.Int:
+		switch {
+		case v == nil:
+		case a.Type == ScriptArgU128:
+			ok = true
+			s.U128(v)
+		case a.Type == ScriptArgU256:
+			ok = true
+			s.U256(v)
+		}
+	case AccountAddress:
+		ok = a.Type == ScriptArgAddress
+		s.Struct(&v)
+	case bool:
+		ok = a.Type == ScriptArgBool
+		s.Bool(v)
+	case []byte:
+		ok = a.Type == ScriptArgU8Vector || a.Type == ScriptArgSerialized
+		s.WriteBytes(v)
+	}
+
+	if !ok {
+		s.SetError(fmt.Errorf("%w: %d %T", ErrScriptArgument, a.Type, a.Value))
+	}
+}
+
+func (a *ScriptArgument) UnmarshalBCS(d *bcs.Deserializer) {
+	a.Type = ScriptArgumentTy(d.Uleb128())
+	switch a.Type {
+	case ScriptArgU8:
+		a.Value = d.U8()
+	case ScriptArgU16:
+		a.Value = d.U16()
+	case ScriptArgU32:
+		a.Value = d.U32()
+	case ScriptArgU64:
+		a.Value = d.U64()
+	case ScriptArgU128:
+		a.Value = d.U128()
+	case ScriptArgU256:
+		a.Value = d.U256()
+	case ScriptArgAddress:
+		var address AccountAddress
+		d.Struct(&address)
+		a.Value = address
+	case ScriptArgBool:
+		a.Value = d.Bool()
+	case ScriptArgU8Vector, ScriptArgSerialized:
+		a.Value = d.ReadBytes()
+	default:
+		d.SetError(fmt.Errorf("%w: variant %d", ErrScriptArgument, a.Type))
+	}
+}
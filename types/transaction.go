@@ -0,0 +1,472 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Transaction types besides PendingTransactionTy
+const (
+	UserTransactionTy            = "user_transaction"
+	GenesisTransactionTy         = "genesis_transaction"
+	BlockMetadataTransactionTy   = "block_metadata_transaction"
+	StateCheckpointTransactionTy = "state_checkpoint_transaction"
+	ValidatorTransactionTy       = "validator_transaction"
+	BlockEpilogueTransactionTy   = "block_epilogue_transaction"
+)
+
+// Write set change types
+const (
+	WriteResourceTy   = "write_resource"
+	DeleteResourceTy  = "delete_resource"
+	WriteModuleTy     = "write_module"
+	DeleteModuleTy    = "delete_module"
+	WriteTableItemTy  = "write_table_item"
+	DeleteTableItemTy = "delete_table_item"
+)
+
+// Payload types besides EntryFunctionPayloadTy and ScriptPayloadTy
+const (
+	MultisigPayloadTy = "multisig_payload"
+	WriteSetPayloadTy = "write_set_payload"
+)
+
+// Signature types besides Ed25519, SingleSender, SingleKeySignature and MultiKeySignature
+const (
+	MultiEd25519Signature = "multi_ed25519_signature"
+	MultiAgentSignature   = "multi_agent_signature"
+	FeePayerSignature     = "fee_payer_signature"
+	NoAccountSignature    = "no_account_signature"
+)
+
+// TypedTransaction is a transaction decoded into the struct of its type,
+// the accessor of that type returns it and the others return false
+type TypedTransaction struct {
+	Type string
+	raw  json.RawMessage
+	tx   interface{}
+}
+
+// TransactionInfo is the outcome every committed transaction has
+type TransactionInfo struct {
+	Version             U64               `json:"version"`
+	Hash                string            `json:"hash"`
+	StateChangeHash     string            `json:"state_change_hash"`
+	EventRootHash       string            `json:"event_root_hash"`
+	StateCheckpointHash string            `json:"state_checkpoint_hash"`
+	GasUsed             U64               `json:"gas_used"`
+	Success             bool              `json:"success"`
+	VMStatus            string            `json:"vm_status"`
+	AccumulatorRootHash string            `json:"accumulator_root_hash"`
+	Changes             []*WriteSetChange `json:"changes"`
+}
+
+// UserTransactionRequest is what the sender of a user transaction signed
+type UserTransactionRequest struct {
+	Sender                  string                `json:"sender"`
+	SequenceNumber          U64                   `json:"sequence_number"`
+	MaxGasAmount            U64                   `json:"max_gas_amount"`
+	GasUnitPrice            U64                   `json:"gas_unit_price"`
+	ExpirationTimestampSecs U64                   `json:"expiration_timestamp_secs"`
+	Payload                 *TransactionPayload   `json:"payload"`
+	Signature               *TransactionSignature `json:"signature"`
+}
+
+type PendingTransaction struct {
+	Hash string `json:"hash"`
+	UserTransactionRequest
+}
+
+type UserTransaction struct {
+	TransactionInfo
+	UserTransactionRequest
+	Events    []TxEvents `json:"events"`
+	Timestamp U64        `json:"timestamp"`
+}
+
+type GenesisTransaction struct {
+	TransactionInfo
+	Payload *TransactionPayload `json:"payload"`
+	Events  []TxEvents          `json:"events"`
+}
+
+type BlockMetadataTransaction struct {
+	TransactionInfo
+	Id                       string     `json:"id"`
+	Epoch                    U64        `json:"epoch"`
+	Round                    U64        `json:"round"`
+	Events                   []TxEvents `json:"events"`
+	PreviousBlockVotesBitvec []uint8    `json:"previous_block_votes_bitvec"`
+	Proposer                 string     `json:"proposer"`
+	FailedProposerIndices    []uint32   `json:"failed_proposer_indices"`
+	Timestamp                U64        `json:"timestamp"`
+}
+
+type StateCheckpointTransaction struct {
+	TransactionInfo
+	Timestamp U64 `json:"timestamp"`
+}
+
+type ValidatorTransaction struct {
+	TransactionInfo
+	Events    []TxEvents `json:"events"`
+	Timestamp U64        `json:"timestamp"`
+}
+
+type BlockEpilogueTransaction struct {
+	TransactionInfo
+	Timestamp    U64             `json:"timestamp"`
+	BlockEndInfo json.RawMessage `json:"block_end_info"`
+}
+
+func (t *TypedTransaction) UnmarshalJSON(b []byte) error {
+	txType, err := variantType(b)
+	if err != nil {
+		return err
+	}
+
+	var tx interface{}
+	switch txType {
+	case PendingTransactionTy:
+		tx = &PendingTransaction{}
+	case UserTransactionTy:
+		tx = &UserTransaction{}
+	case GenesisTransactionTy:
+		tx = &GenesisTransaction{}
+	case BlockMetadataTransactionTy:
+		tx = &BlockMetadataTransaction{}
+	case StateCheckpointTransactionTy:
+		tx = &StateCheckpointTransaction{}
+	case ValidatorTransactionTy:
+		tx = &ValidatorTransaction{}
+	case BlockEpilogueTransactionTy:
+		tx = &BlockEpilogueTransaction{}
+	}
+
+	if tx != nil {
+		if err := json.Unmarshal(b, tx); err != nil {
+			return fmt.Errorf("%s: %w", txType, err)
+		}
+	}
+
+	t.Type, t.raw, t.tx = txType, append(json.RawMessage(nil), b...), tx
+	return nil
+}
+
+// MarshalJSON returns the JSON t was decoded from
+func (t *TypedTransaction) MarshalJSON() ([]byte, error) {
+	return rawJSON(t.raw), nil
+}
+
+// Info returns the outcome of committed transactions, false for pending and unknown transactions
+func (t *TypedTransaction) Info() (*TransactionInfo, bool) {
+	switch tx := t.tx.(type) {
+	case *UserTransaction:
+		return &tx.TransactionInfo, true
+	case *GenesisTransaction:
+		return &tx.TransactionInfo, true
+	case *BlockMetadataTransaction:
+		return &tx.TransactionInfo, true
+	case *StateCheckpointTransaction:
+		return &tx.TransactionInfo, true
+	case *ValidatorTransaction:
+		return &tx.TransactionInfo, true
+	case *BlockEpilogueTransaction:
+		return &tx.TransactionInfo, true
+	}
+	return nil, false
+}
+
+// Events returns the events of the transaction, nil for transactions without events
+func (t *TypedTransaction) Events() []TxEvents {
+	switch tx := t.tx.(type) {
+	case *UserTransaction:
+		return tx.Events
+	case *GenesisTransaction:
+		return tx.Events
+	case *BlockMetadataTransaction:
+		return tx.Events
+	case *ValidatorTransaction:
+		return tx.Events
+	}
+	return nil
+}
+
+func (t *TypedTransaction) Pending() (*PendingTransaction, bool) {
+	tx, ok := t.tx.(*PendingTransaction)
+	return tx, ok
+}
+
+func (t *TypedTransaction) User() (*UserTransaction, bool) {
+	tx, ok := t.tx.(*UserTransaction)
+	return tx, ok
+}
+
+func (t *TypedTransaction) Genesis() (*GenesisTransaction, bool) {
+	tx, ok := t.tx.(*GenesisTransaction)
+	return tx, ok
+}
+
+func (t *TypedTransaction) BlockMetadata() (*BlockMetadataTransaction, bool) {
+	tx, ok := t.tx.(*BlockMetadataTransaction)
+	return tx, ok
+}
+
+func (t *TypedTransaction) StateCheckpoint() (*StateCheckpointTransaction, bool) {
+	tx, ok := t.tx.(*StateCheckpointTransaction)
+	return tx, ok
+}
+
+func (t *TypedTransaction) Validator() (*ValidatorTransaction, bool) {
+	tx, ok := t.tx.(*ValidatorTransaction)
+	return tx, ok
+}
+
+func (t *TypedTransaction) BlockEpilogue() (*BlockEpilogueTransaction, bool) {
+	tx, ok := t.tx.(*BlockEpilogueTransaction)
+	return tx, ok
+}
+
+// WriteSetChange is a change of a committed transaction, the fields set depend on Type:
+// Address for resources and modules, Resource and Module for deletions, Handle, Key and Value
+// for table items and Data for the written resource or module and the decoded table item
+type WriteSetChange struct {
+	Type         string          `json:"type"`
+	StateKeyHash string          `json:"state_key_hash"`
+	Address      string          `json:"address,omitempty"`
+	Resource     string          `json:"resource,omitempty"`
+	Module       string          `json:"module,omitempty"`
+	Handle       string          `json:"handle,omitempty"`
+	Key          string          `json:"key,omitempty"`
+	Value        string          `json:"value,omitempty"`
+	Data         json.RawMessage `json:"data,omitempty"`
+}
+
+// DecodedTableData is the table item of a table change decoded by the node, Value and ValueType
+// are empty for deletions
+type DecodedTableData struct {
+	Key       json.RawMessage `json:"key"`
+	KeyType   string          `json:"key_type"`
+	Value     json.RawMessage `json:"value"`
+	ValueType string          `json:"value_type"`
+}
+
+// WriteResource returns the resource a write_resource change writes
+func (c *WriteSetChange) WriteResource() (*AccountResource, bool) {
+	if c.Type != WriteResourceTy {
+		return nil, false
+	}
+	return decodeVariant[AccountResource](c.Data)
+}
+
+// WriteModule returns the module a write_module change writes
+func (c *WriteSetChange) WriteModule() (*AccountModule, bool) {
+	if c.Type != WriteModuleTy {
+		return nil, false
+	}
+	return decodeVariant[AccountModule](c.Data)
+}
+
+// TableData returns the decoded item of write_table_item and delete_table_item changes,
+// false when the node did not decode it
+func (c *WriteSetChange) TableData() (*DecodedTableData, bool) {
+	if c.Type != WriteTableItemTy && c.Type != DeleteTableItemTy {
+		return nil, false
+	}
+	return decodeVariant[DecodedTableData](c.Data)
+}
+
+// TransactionPayload is the payload of a user or genesis transaction, the accessor of its Type decodes it
+type TransactionPayload struct {
+	Type string
+	raw  json.RawMessage
+}
+
+// MultisigPayload executes the transaction a multisig account approved, TransactionPayload is nil
+// when the payload was stored on chain
+type MultisigPayload struct {
+	MultisigAddress    string                `json:"multisig_address"`
+	TransactionPayload *EntryFunctionPayload `json:"transaction_payload"`
+}
+
+// WriteSetPayload is the payload of the genesis transaction
+type WriteSetPayload struct {
+	WriteSet json.RawMessage `json:"write_set"`
+}
+
+func (p *TransactionPayload) UnmarshalJSON(b []byte) error {
+	t, err := variantType(b)
+	if err != nil {
+		return err
+	}
+	p.Type, p.raw = t, append(json.RawMessage(nil), b...)
+	return nil
+}
+
+func (p *TransactionPayload) MarshalJSON() ([]byte, error) {
+	return rawJSON(p.raw), nil
+}
+
+func (p *TransactionPayload) EntryFunction() (*EntryFunctionPayload, bool) {
+	if p.Type != EntryFunctionPayloadTy {
+		return nil, false
+	}
+	return decodeVariant[EntryFunctionPayload](p.raw)
+}
+
+func (p *TransactionPayload) Script() (*ScriptPayload, bool) {
+	if p.Type != ScriptPayloadTy {
+		return nil, false
+	}
+	return decodeVariant[ScriptPayload](p.raw)
+}
+
+func (p *TransactionPayload) Multisig() (*MultisigPayload, bool) {
+	if p.Type != MultisigPayloadTy {
+		return nil, false
+	}
+	return decodeVariant[MultisigPayload](p.raw)
+}
+
+func (p *TransactionPayload) WriteSet() (*WriteSetPayload, bool) {
+	if p.Type != WriteSetPayloadTy {
+		return nil, false
+	}
+	return decodeVariant[WriteSetPayload](p.raw)
+}
+
+// TransactionSignature is the signature of a user transaction or of one of its signers,
+// the accessor of its Type decodes it
+type TransactionSignature struct {
+	Type string
+	raw  json.RawMessage
+}
+
+type Ed25519Authenticator struct {
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature"`
+}
+
+type MultiEd25519Authenticator struct {
+	PublicKeys []string `json:"public_keys"`
+	Signatures []string `json:"signatures"`
+	Threshold  uint8    `json:"threshold"`
+	Bitmap     string   `json:"bitmap"`
+}
+
+// MultiAgentAuthenticator is signed by the sender and every secondary signer, FeePayerAddress and
+// FeePayerSigner are set for fee_payer_signature
+type MultiAgentAuthenticator struct {
+	Sender                   *TransactionSignature   `json:"sender"`
+	SecondarySignerAddresses []string                `json:"secondary_signer_addresses"`
+	SecondarySigners         []*TransactionSignature `json:"secondary_signers"`
+	FeePayerAddress          string                  `json:"fee_payer_address,omitempty"`
+	FeePayerSigner           *TransactionSignature   `json:"fee_payer_signer,omitempty"`
+}
+
+func (s *TransactionSignature) UnmarshalJSON(b []byte) error {
+	t, err := variantType(b)
+	if err != nil {
+		return err
+	}
+	s.Type, s.raw = t, append(json.RawMessage(nil), b...)
+	return nil
+}
+
+func (s *TransactionSignature) MarshalJSON() ([]byte, error) {
+	return rawJSON(s.raw), nil
+}
+
+func (s *TransactionSignature) Ed25519() (*Ed25519Authenticator, bool) {
+	if s.Type != Ed25519 {
+		return nil, false
+	}
+	return decodeVariant[Ed25519Authenticator](s.raw)
+}
+
+func (s *TransactionSignature) MultiEd25519() (*MultiEd25519Authenticator, bool) {
+	if s.Type != MultiEd25519Signature {
+		return nil, false
+	}
+	return decodeVariant[MultiEd25519Authenticator](s.raw)
+}
+
+// MultiAgent decodes multi_agent_signature and fee_payer_signature
+func (s *TransactionSignature) MultiAgent() (*MultiAgentAuthenticator, bool) {
+	if s.Type != MultiAgentSignature && s.Type != FeePayerSignature {
+		return nil, false
+	}
+	return decodeVariant[MultiAgentAuthenticator](s.raw)
+}
+
+// SingleSender returns the signature of a single_sender transaction
+func (s *TransactionSignature) SingleSender() (*AccountSignature, bool) {
+	if s.Type != SingleSender {
+		return nil, false
+	}
+
+	var sig struct {
+		Sender *AccountSignature `json:"sender"`
+	}
+	if json.Unmarshal(s.raw, &sig) != nil || sig.Sender == nil {
+		return nil, false
+	}
+	return sig.Sender, true
+}
+
+// AnyKey returns the single_key_signature and multi_key_signature signers of multi agent transactions
+func (s *TransactionSignature) AnyKey() (*AccountSignature, bool) {
+	if s.Type != SingleKeySignature && s.Type != MultiKeySignature {
+		return nil, false
+	}
+	return decodeVariant[AccountSignature](s.raw)
+}
+
+// Typed decodes t into the struct of its type, t must be decoded from JSON
+func (t *Transaction) Typed() (*TypedTransaction, error) {
+	if t.raw == nil {
+		return nil, ErrTxRawNull
+	}
+
+	typed := &TypedTransaction{}
+	if err := json.Unmarshal(t.raw, typed); err != nil {
+		return nil, err
+	}
+	return typed, nil
+}
+
+func (t *Transaction) UnmarshalJSON(b []byte) error {
+	type transaction Transaction
+	if err := json.Unmarshal(b, (*transaction)(t)); err != nil {
+		return err
+	}
+	t.raw = append(json.RawMessage(nil), b...)
+	return nil
+}
+
+func variantType(b []byte) (string, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+	err := json.Unmarshal(b, &header)
+	return header.Type, err
+}
+
+func rawJSON(raw json.RawMessage) []byte {
+	if raw == nil {
+		return []byte("null")
+	}
+	return raw
+}
+
+func decodeVariant[T any](raw json.RawMessage) (*T, bool) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, false
+	}
+
+	v := new(T)
+	if json.Unmarshal(raw, v) != nil {
+		return nil, false
+	}
+	return v, true
+}
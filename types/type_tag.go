@@ -0,0 +1,260 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/threeandtwo/aptclient/bcs"
+)
+
+// TypeTagTy is the BCS variant of a Move type tag
+type TypeTagTy uint8
+
+const (
+	BoolTag TypeTagTy = iota
+	U8Tag
+	U64Tag
+	U128Tag
+	AddressTag
+	SignerTag
+	VectorTag
+	StructTag
+	U16Tag
+	U32Tag
+	U256Tag
+)
+
+var primitiveTags = map[string]TypeTagTy{
+	"bool":    BoolTag,
+	"u8":      U8Tag,
+	"u16":     U16Tag,
+	"u32":     U32Tag,
+	"u64":     U64Tag,
+	"u128":    U128Tag,
+	"u256":    U256Tag,
+	"address": AddressTag,
+	"signer":  SignerTag,
+}
+
+// TypeTag is a Move type like u64, vector<u8> or 0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>,
+// Elem is set for VectorTag and Struct for StructTag
+type TypeTag struct {
+	Type   TypeTagTy
+	Elem   *TypeTag
+	Struct *StructTagValue
+}
+
+type StructTagValue struct {
+	Address  AccountAddress
+	Module   string
+	Name     string
+	TypeArgs []*TypeTag
+}
+
+// ParseTypeTag parses the Move type s, spaces between tokens are ignored
+func ParseTypeTag(s string) (*TypeTag, error) {
+	p := &typeTagParser{s: s}
+	tag, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos != len(p.s) {
+		return nil, ErrTypeTag
+	}
+	return tag, nil
+}
+
+// ParseTypeTags parses every type of typeArgs
+func ParseTypeTags(typeArgs []string) ([]*TypeTag, error) {
+	tags := make([]*TypeTag, 0, len(typeArgs))
+	for _, typeArg := range typeArgs {
+		tag, err := ParseTypeTag(typeArg)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func (t *TypeTag) String() string {
+	switch t.Type {
+	case VectorTag:
+		return "vector<" + t.Elem.String() + ">"
+	case StructTag:
+		return t.Struct.String()
+	}
+
+	for name, ty := range primitiveTags {
+		if ty == t.Type {
+			return name
+		}
+	}
+	return ""
+}
+
+func (t *StructTagValue) String() string {
+	s := t.Address.String() + "::" + t.Module + "::" + t.Name
+	if len(t.TypeArgs) == 0 {
+		return s
+	}
+
+	args := make([]string, 0, len(t.TypeArgs))
+	for _, arg := range t.TypeArgs {
+		args = append(args, arg.String())
+	}
+	return s + "<" + strings.Join(args, ", ") + ">"
+}
+
+func (t *TypeTag) MarshalBCS(s *bcs.Serializer) {
+	s.Uleb128(uint32(t.Type))
+	switch t.Type {
+	case VectorTag:
+		if t.Elem == nil {
+			s.SetError(ErrTypeTag)
+			return
+		}
+		s.Struct(t.Elem)
+	case StructTag:
+		if t.Struct == nil {
+			s.SetError(ErrTypeTag)
+			return
+		}
+		s.Struct(t.Struct)
+	}
+}
+
+func (t *TypeTag) UnmarshalBCS(d *bcs.Deserializer) {
+	t.Type = TypeTagTy(d.Uleb128())
+	switch t.Type {
+	case VectorTag:
+		t.Elem = &TypeTag{}
+		d.Struct(t.Elem)
+	case StructTag:
+		t.Struct = &StructTagValue{}
+		d.Struct(t.Struct)
+	default:
+		if t.Type > U256Tag {
+			d.SetError(bcs.ErrInvalidVariant)
+		}
+	}
+}
+
+func (t *StructTagValue) MarshalBCS(s *bcs.Serializer) {
+	s.Struct(&t.Address)
+	s.WriteStr(t.Module)
+	s.WriteStr(t.Name)
+	bcs.SerializeSequence(s, t.TypeArgs)
+}
+
+func (t *StructTagValue) UnmarshalBCS(d *bcs.Deserializer) {
+	d.Struct(&t.Address)
+	t.Module = d.ReadStr()
+	t.Name = d.ReadStr()
+	t.TypeArgs = bcs.DeserializeSequence(d, func() *TypeTag { return &TypeTag{} })
+}
+
+type typeTagParser struct {
+	s   string
+	pos int
+}
+
+func (p *typeTagParser) parse() (*TypeTag, error) {
+	p.skipSpace()
+	token := p.token()
+
+	if ty, ok := primitiveTags[token]; ok {
+		return &TypeTag{Type: ty}, nil
+	}
+
+	if token == "vector" {
+		args, err := p.typeArgs()
+		if err != nil {
+			return nil, err
+		}
+		if len(args) != 1 {
+			return nil, ErrTypeTag
+		}
+		return &TypeTag{Type: VectorTag, Elem: args[0]}, nil
+	}
+
+	parts := strings.Split(token, "::")
+	if len(parts) != 3 || !isIdentifier(parts[1]) || !isIdentifier(parts[2]) {
+		return nil, ErrTypeTag
+	}
+
+	address, err := ParseAccountAddress(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &StructTagValue{Address: address, Module: parts[1], Name: parts[2]}
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == '<' {
+		if tag.TypeArgs, err = p.typeArgs(); err != nil {
+			return nil, err
+		}
+	}
+	return &TypeTag{Type: StructTag, Struct: tag}, nil
+}
+
+// typeArgs parses <T1, T2, ...>
+func (p *typeTagParser) typeArgs() ([]*TypeTag, error) {
+	if !p.consume('<') {
+		return nil, ErrTypeTag
+	}
+
+	var args []*TypeTag
+	for {
+		arg, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		if p.consume(',') {
+			continue
+		}
+		if p.consume('>') {
+			return args, nil
+		}
+		return nil, ErrTypeTag
+	}
+}
+
+func (p *typeTagParser) token() string {
+	start := p.pos
+	for p.pos < len(p.s) && !strings.ContainsRune("<>, \t\n", rune(p.s[p.pos])) {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *typeTagParser) consume(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *typeTagParser) skipSpace() {
+	for p.pos < len(p.s) && strings.ContainsRune(" \t\n", rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		if c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && c >= '0' && c <= '9' {
+			continue
+		}
+		return false
+	}
+	return true
+}
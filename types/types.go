@@ -1,6 +1,11 @@
 package types
 
-import "crypto/ed25519"
+import (
+	"crypto/ed25519"
+	"encoding/json"
+
+	"github.com/btcsuite/btcd/btcec"
+)
 
 type KeyTy int
 
@@ -10,6 +15,13 @@ const (
 	NoneTy
 )
 
+type CurveTy int
+
+const (
+	Ed25519Curve CurveTy = iota
+	Secp256k1Curve
+)
+
 type BlockWithTxs string
 
 const (
@@ -20,7 +32,32 @@ const (
 const (
 	AptResourceTy = "0x1::coin::CoinStore<0x1::aptos_coin::AptosCoin>"
 	AptAccountTy  = "0x1::account::Account"
-	Ed25519       = "ed25519_signature"
+	AptCoinTy     = "0x1::aptos_coin::AptosCoin"
+	CoinStoreTy   = "0x1::coin::CoinStore"
+	CoinInfoTy    = "0x1::coin::CoinInfo"
+
+	// AptMetadataAddress is the fungible asset metadata paired with AptCoinTy
+	AptMetadataAddress           = "0xa"
+	FungibleStoreTy              = "0x1::fungible_asset::FungibleStore"
+	ConcurrentFungibleBalanceTy  = "0x1::fungible_asset::ConcurrentFungibleBalance"
+	ObjectCoreTy                 = "0x1::object::ObjectCore"
+	FungibleAssetMetadataTy      = "0x1::fungible_asset::Metadata"
+	CoinConversionMapTy          = "0x1::coin::CoinConversionMap"
+	DirectTransferConfigTy       = "0x1::aptos_account::DirectTransferConfig"
+	PrimaryFungibleStoreTransfer = "0x1::primary_fungible_store::transfer"
+	Ed25519                      = "ed25519_signature"
+
+	EntryFunctionPayloadTy = "entry_function_payload"
+	ScriptPayloadTy        = "script_payload"
+	PendingTransactionTy   = "pending_transaction"
+
+	SingleSender       = "single_sender"
+	SingleKeySignature = "single_key_signature"
+	MultiKeySignature  = "multi_key_signature"
+
+	// AnyPublicKey and AnySignature types
+	AnyEd25519     = "ed25519"
+	Secp256k1Ecdsa = "secp256k1_ecdsa"
 )
 
 type NodeHealth struct {
@@ -64,6 +101,10 @@ type AptAccount struct {
 	PublicKey  string
 	PrivateKey ed25519.PrivateKey
 	AuthKey    string
+
+	// Curve is types.Ed25519Curve by default, Secp256k1Key is set for types.Secp256k1Curve accounts
+	Curve        CurveTy
+	Secp256k1Key *btcec.PrivateKey
 }
 
 type Account struct {
@@ -82,24 +123,35 @@ type AccountModule struct {
 }
 
 type MoveModuleABI struct {
-	Address string   `json:"address"`
-	Name    string   `json:"string"`
-	Friends []string `json:"friends"`
+	Address          string          `json:"address"`
+	Name             string          `json:"name"`
+	Friends          []string        `json:"friends"`
+	ExposedFunctions []*MoveFunction `json:"exposed_functions"`
+	Structs          []*MoveStruct   `json:"structs"`
 }
 
 type MoveFunction struct {
-	Name              string        `json:"name"`
-	Visibility        string        `json:"visibility"`
-	GenericTypeParams []interface{} `json:"generic_type_params"`
-	Params            []string      `json:"params"`
-	Returns           []string      `json:"returns"`
+	Name              string                  `json:"name"`
+	Visibility        string                  `json:"visibility"`
+	IsEntry           bool                    `json:"is_entry"`
+	IsView            bool                    `json:"is_view"`
+	GenericTypeParams []*MoveGenericTypeParam `json:"generic_type_params"`
+	Params            []string                `json:"params"`
+	Returns           []string                `json:"return"`
 }
 
 type MoveStruct struct {
-	Name              string        `json:"name"`
-	IsNative          bool          `json:"is_native"`
-	Abilities         []string      `json:"abilities"`
-	GenericTypeParams []interface{} `json:"generic_type_params"`
+	Name              string                  `json:"name"`
+	IsNative          bool                    `json:"is_native"`
+	IsEvent           bool                    `json:"is_event"`
+	Abilities         []string                `json:"abilities"`
+	GenericTypeParams []*MoveGenericTypeParam `json:"generic_type_params"`
+	Fields            []*MoveStructField      `json:"fields"`
+}
+
+// MoveGenericTypeParam lists the abilities a type argument needs, like copy or store
+type MoveGenericTypeParam struct {
+	Constraints []string `json:"constraints"`
 }
 
 type MoveStructField struct {
@@ -107,6 +159,26 @@ type MoveStructField struct {
 	Type string `json:"type"`
 }
 
+// Function returns the exposed function name
+func (m *MoveModuleABI) Function(name string) (*MoveFunction, bool) {
+	for _, f := range m.ExposedFunctions {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// Struct returns the struct name
+func (m *MoveModuleABI) Struct(name string) (*MoveStruct, bool) {
+	for _, st := range m.Structs {
+		if st.Name == name {
+			return st, true
+		}
+	}
+	return nil, false
+}
+
 type Transaction struct {
 	Type                    string      `json:"type"`
 	Sender                  string      `json:"sender"`
@@ -125,6 +197,9 @@ type Transaction struct {
 	VMStatus                string      `json:"vm_status"`
 	AccumulatorRootHash     string      `json:"accumulator_root_hash"`
 	Changes                 []TxChange  `json:"changes"`
+
+	// raw is the JSON the transaction is decoded from, for Typed
+	raw json.RawMessage
 }
 
 type SimulateTx struct {
@@ -156,16 +231,15 @@ type TxChange struct {
 	Module       string `json:"module"`
 }
 
+// TxEvents is an event of a transaction, Decoded is the Data decoded by DefaultEventRegistry
+// or nil when its type is not registered
 type TxEvents struct {
-	Key            string      `json:"key"`
-	SequenceNumber string      `json:"sequence_number"`
-	Type           string      `json:"type"`
-	Data           TxEventData `json:"data"`
-}
-
-type TxEventData struct {
-	Created string `json:"created"`
-	RoleId  string `json:"role_id"`
+	Key            string          `json:"key"`
+	Guid           EventGuid       `json:"guid"`
+	SequenceNumber string          `json:"sequence_number"`
+	Type           string          `json:"type"`
+	Data           json.RawMessage `json:"data"`
+	Decoded        interface{}     `json:"-"`
 }
 
 type UnsignedTx struct {
@@ -184,38 +258,75 @@ type SignedTx struct {
 }
 
 type TxSignature struct {
-	Type      string `json:"type"`
-	PublicKey string `json:"public_key"`
-	Signature string `json:"signature"`
+	Type      string            `json:"type"`
+	PublicKey string            `json:"public_key,omitempty"`
+	Signature string            `json:"signature,omitempty"`
+	Sender    *AccountSignature `json:"sender,omitempty"`
 }
 
 type EntryFunctionPayload struct {
 	Type          string   `json:"type"`
 	Function      string   `json:"function"`
 	TypeArguments []string `json:"type_arguments"`
-	Arguments     []string `json:"arguments"`
+	// Arguments are the JSON values of the Move arguments, vector<u8> is a 0x hex string
+	// and other vectors are slices of their elements
+	Arguments []interface{} `json:"arguments"`
 }
 
 type SigningMessage struct {
 	Message string `json:"message"`
 }
 
+// SignMessagePayload is the wallet standard signMessage input,
+// Application and ChainId are left out of the full message when empty
+type SignMessagePayload struct {
+	Address     bool   `json:"address"`
+	Application string `json:"application"`
+	ChainId     uint8  `json:"chainId"`
+	Message     string `json:"message"`
+	Nonce       string `json:"nonce"`
+}
+
+// SignMessageResponse is the wallet standard signMessage output, Signature signs FullMessage
+type SignMessageResponse struct {
+	Address     string `json:"address,omitempty"`
+	Application string `json:"application,omitempty"`
+	ChainId     uint8  `json:"chainId,omitempty"`
+	FullMessage string `json:"fullMessage"`
+	Message     string `json:"message"`
+	Nonce       string `json:"nonce"`
+	Prefix      string `json:"prefix"`
+	Signature   string `json:"signature"`
+}
+
+// ExceptionMsg codes of missing state
+const (
+	ResourceNotFoundCode    = "resource_not_found"
+	TableItemNotFoundCode   = "table_item_not_found"
+	TransactionNotFoundCode = "transaction_not_found"
+	VersionPrunedCode       = "version_pruned"
+	InvalidInputCode        = "invalid_input"
+)
+
+// VersionAheadMsg is in the InvalidInputCode message of a start version past the ledger head
+const VersionAheadMsg = "higher than the current ledger version"
+
 type ExceptionMsg struct {
 	Message       string `json:"message"`
 	Code          string `json:"error_code"`
 	LedgerVersion string `json:"ledger_version"`
 }
 
+// Event is an event of an event handle, Decoded is the Data decoded by DefaultEventRegistry
+// or nil when its type is not registered
 type Event struct {
-	Version string `json:"version"`
-	Key     string `json:"key"`
-	Guid    struct {
-		CreationNumber string `json:"creation_number"`
-		AccountAddress string `json:"account_address"`
-	} `json:"guid"`
-	SequenceNumber string      `json:"sequence_number"`
-	Type           string      `json:"type"`
-	Data           interface{} `json:"data"`
+	Version        string          `json:"version"`
+	Key            string          `json:"key"`
+	Guid           EventGuid       `json:"guid"`
+	SequenceNumber string          `json:"sequence_number"`
+	Type           string          `json:"type"`
+	Data           json.RawMessage `json:"data"`
+	Decoded        interface{}     `json:"-"`
 }
 
 type EstimateGasPrice struct {